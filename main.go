@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pretty-discord-alerts/pkg/discord"
@@ -25,73 +28,143 @@ func must(err error, status int, message string) {
 	}
 }
 
-func main() {
-	// Configure logging
-	logLevel := slog.LevelInfo
-	
-	// Support both DEBUG=true and LOG_LEVEL=debug/info/warn/error
-	if os.Getenv("DEBUG") == "true" {
-		logLevel = slog.LevelDebug
-	} else if level := os.Getenv("LOG_LEVEL"); level != "" {
-		switch level {
-		case "debug":
-			logLevel = slog.LevelDebug
-		case "info":
-			logLevel = slog.LevelInfo
-		case "warn":
-			logLevel = slog.LevelWarn
-		case "error":
-			logLevel = slog.LevelError
+// envBool reports whether an environment variable is set to "true"
+func envBool(key string) bool {
+	return os.Getenv(key) == "true"
+}
+
+// envInt returns the integer value of an environment variable, or def if unset or invalid
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Ignoring invalid integer environment variable", "key", key, "value", value)
+		return def
+	}
+	return n
+}
+
+// envDuration returns the duration value of an environment variable, or def if unset or invalid
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Ignoring invalid duration environment variable", "key", key, "value", value)
+		return def
+	}
+	return d
+}
+
+// envList returns the comma-separated values of an environment variable
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
 		}
 	}
-	
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: logLevel,
-	}))
-	slog.SetDefault(logger)
+	return values
+}
+
+// envMap returns the comma-separated key=value pairs of an environment variable
+func envMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range envList(key) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			slog.Warn("Ignoring invalid key=value pair in environment variable", "key", key, "value", pair)
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// newSender creates the Discord sender selected by the environment: a bot
+// when DISCORD_BOT_TOKEN is set, a webhook otherwise
+func newSender(opts []discord.Option) (discord.Sender, error) {
+	if token := os.Getenv("DISCORD_BOT_TOKEN"); token != "" {
+		channelID := os.Getenv("DISCORD_CHANNEL_ID")
+		if channelID == "" {
+			return nil, errors.New("DISCORD_CHANNEL_ID environment variable is required with DISCORD_BOT_TOKEN")
+		}
+		// Bots post to a single channel and cannot create forum posts
+		if envBool("DISCORD_FORUM_THREADS") {
+			return nil, errors.New("DISCORD_FORUM_THREADS is not supported with DISCORD_BOT_TOKEN")
+		}
+		slog.Info("Delivering alerts as a bot", "channel_id", channelID)
+		return discord.NewBot(token, channelID, opts...), nil
+	}
 
 	discordWebhookURL := os.Getenv("DISCORD_WEBHOOK_URL")
 	if discordWebhookURL == "" {
-		slog.Error("DISCORD_WEBHOOK_URL environment variable is required")
-		os.Exit(1)
+		return nil, errors.New("DISCORD_WEBHOOK_URL or DISCORD_BOT_TOKEN environment variable is required")
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8888"
+	webhookURL, err := discord.ParseWebhookURL(discordWebhookURL, os.Getenv("DISCORD_API_BASE_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISCORD_WEBHOOK_URL: %w", err)
 	}
 
-	webhook := discord.NewWebhook(discordWebhookURL)
-	router := http.NewServeMux()
+	webhook := discord.NewWebhook(discordWebhookURL, opts...)
+	if threadID := os.Getenv("DISCORD_THREAD_ID"); threadID != "" {
+		// Discord rejects messages naming both an existing thread and a new post
+		if envBool("DISCORD_FORUM_THREADS") {
+			return nil, errors.New("DISCORD_THREAD_ID and DISCORD_FORUM_THREADS cannot be used together")
+		}
+		webhook = webhook.Thread(threadID)
+	}
 
-	router.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	if envBool("DISCORD_VERIFY_WEBHOOK") {
+		info, err := webhook.Info(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify webhook %s: %w", webhookURL.Redacted(), err)
+		}
+		slog.Info("Verified Discord webhook",
+			"webhook", webhookURL.Redacted(),
+			"name", info.Name,
+			"channel_id", info.ChannelID,
+			"guild_id", info.GuildID,
+		)
+	}
 
-	router.HandleFunc("GET /ready", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	slog.Info("Delivering alerts through a webhook", "webhook", webhookURL.Redacted())
+	return webhook, nil
+}
 
-	// Prometheus metrics endpoint
-	router.Handle("GET /metrics", promhttp.Handler())
+// webhookConfig controls how Grafana webhooks are forwarded to Discord
+type webhookConfig struct {
+	transformOpts []transformer.Option
+	attachPayload bool
+	// severityLabel is the label recorded as the severity of alert metrics
+	severityLabel string
+}
 
-	router.HandleFunc("POST /webhook", middleware.RecoverMiddleware(func(w http.ResponseWriter, r *http.Request) {
+// newWebhookHandler returns the handler forwarding Grafana webhooks to Discord
+func newWebhookHandler(sender discord.Sender, cfg webhookConfig) http.HandlerFunc {
+	return middleware.RecoverMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		
+
 		// Read raw request body
 		bodyBytes, err := io.ReadAll(r.Body)
 		must(err, http.StatusBadRequest, "Failed to read request body")
-		
+
 		// Debug logging
 		slog.Debug("Received webhook request", "body", string(bodyBytes))
-		
+
 		// Decode payload
 		var payload grafana.WebhookPayload
 		must(json.Unmarshal(bodyBytes, &payload), http.StatusBadRequest, "Invalid request body")
 
 		// Record alert metrics
 		for _, alert := range payload.Alerts {
-			severity := alert.Labels["severity"]
+			severity := alert.Labels[cfg.severityLabel]
 			if severity == "" {
 				severity = "none"
 			}
@@ -99,11 +172,32 @@ func main() {
 		}
 
 		// Transform and send to Discord
-		discordMsgs := transformer.GrafanaToDiscord(&payload)
+		discordMsgs := transformer.GrafanaToDiscord(&payload, cfg.transformOpts...)
 		for _, discordMsg := range discordMsgs {
+			if cfg.attachPayload {
+				discordMsg.Files = append(discordMsg.Files, discord.File{
+					Name:        "payload.json",
+					ContentType: "application/json",
+					Data:        bodyBytes,
+				})
+			}
+			if err := discordMsg.Validate(); err != nil {
+				slog.Info("Splitting message to fit Discord limits", "error", err)
+			}
+
 			discordStart := time.Now()
-			err = webhook.Send(discordMsg)
+			err = sender.SendSplit(r.Context(), discordMsg)
 			metrics.RecordDiscordSend(err == nil, time.Since(discordStart))
+			var apiErr *discord.APIError
+			if errors.As(err, &apiErr) {
+				slog.Error("Discord rejected message",
+					"status", apiErr.StatusCode,
+					"discord_code", apiErr.Code,
+					"discord_message", apiErr.Message,
+					"field_errors", apiErr.FieldErrors(),
+				)
+				metrics.RecordDiscordError(apiErr.StatusCode, apiErr.Code)
+			}
 			must(err, http.StatusInternalServerError, "Failed to forward to Discord")
 		}
 
@@ -118,7 +212,153 @@ func main() {
 		metrics.RecordWebhookRequest("success")
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
-	}, "/webhook"))
+	}, "/webhook")
+}
+
+func main() {
+	// Configure logging
+	logLevel := slog.LevelInfo
+
+	// Support both DEBUG=true and LOG_LEVEL=debug/info/warn/error
+	if os.Getenv("DEBUG") == "true" {
+		logLevel = slog.LevelDebug
+	} else if level := os.Getenv("LOG_LEVEL"); level != "" {
+		switch level {
+		case "debug":
+			logLevel = slog.LevelDebug
+		case "info":
+			logLevel = slog.LevelInfo
+		case "warn":
+			logLevel = slog.LevelWarn
+		case "error":
+			logLevel = slog.LevelError
+		}
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+	slog.SetDefault(logger)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8888"
+	}
+
+	retry := discord.DefaultRetryPolicy()
+	retry.MaxAttempts = envInt("DISCORD_RETRY_MAX_ATTEMPTS", retry.MaxAttempts)
+	retry.BaseBackoff = envDuration("DISCORD_RETRY_BASE_BACKOFF", retry.BaseBackoff)
+	retry.MaxBackoff = envDuration("DISCORD_RETRY_MAX_BACKOFF", retry.MaxBackoff)
+
+	discordOpts := []discord.Option{
+		discord.WithTimeout(envDuration("DISCORD_TIMEOUT", discord.DefaultTimeout)),
+		discord.WithRetryPolicy(retry),
+	}
+	if userAgent := os.Getenv("DISCORD_USER_AGENT"); userAgent != "" {
+		discordOpts = append(discordOpts, discord.WithUserAgent(userAgent))
+	}
+	if apiBase := os.Getenv("DISCORD_API_BASE_URL"); apiBase != "" {
+		discordOpts = append(discordOpts, discord.WithAPIBase(apiBase))
+	}
+
+	if proxyURL, caFile := os.Getenv("DISCORD_PROXY_URL"), os.Getenv("DISCORD_CA_FILE"); proxyURL != "" || caFile != "" {
+		httpClient, err := discord.NewHTTPClient(discord.TransportConfig{ProxyURL: proxyURL, CAFile: caFile})
+		if err != nil {
+			slog.Error("Invalid Discord transport configuration", "error", err)
+			os.Exit(1)
+		}
+		discordOpts = append(discordOpts, discord.WithHTTPClient(httpClient))
+	}
+
+	sender, err := newSender(discordOpts)
+	if err != nil {
+		slog.Error("Invalid Discord configuration", "error", err)
+		os.Exit(1)
+	}
+
+	webhookCfg := webhookConfig{
+		attachPayload: envBool("DISCORD_ATTACH_PAYLOAD"),
+		severityLabel: transformer.DefaultSeverityProfile().Label,
+	}
+	if envBool("DISCORD_FORUM_THREADS") {
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithThreadNames())
+	}
+	if envBool("DISCORD_GROUP_ALERTS") {
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithGrouping())
+	}
+	if path := os.Getenv("DISCORD_SEVERITY_PROFILE"); path != "" {
+		profile, err := transformer.LoadSeverityProfile(path)
+		if err != nil {
+			slog.Error("Invalid DISCORD_SEVERITY_PROFILE", "error", err)
+			os.Exit(1)
+		}
+		webhookCfg.severityLabel = profile.Label
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithSeverityProfile(profile))
+	}
+	if include, exclude := envList("DISCORD_LABEL_FIELDS"), envList("DISCORD_LABEL_FIELDS_EXCLUDE"); include != nil || exclude != nil {
+		labelFields := transformer.LabelFields{
+			Include: include,
+			Exclude: exclude,
+			Names:   envMap("DISCORD_LABEL_NAMES"),
+			Inline:  os.Getenv("DISCORD_LABEL_FIELDS_INLINE") != "false",
+		}
+		if err := labelFields.Validate(); err != nil {
+			slog.Error("Invalid DISCORD_LABEL_FIELDS", "error", err)
+			os.Exit(1)
+		}
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithLabelFields(labelFields))
+	}
+	webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithSilenceOptions(transformer.SilenceOptions{
+		ExcludeLabels: envList("DISCORD_SILENCE_EXCLUDE_LABELS"),
+		Duration:      envDuration("DISCORD_SILENCE_DURATION", 0),
+		Comment:       os.Getenv("DISCORD_SILENCE_COMMENT"),
+	}))
+	if refIDs := envList("DISCORD_HIDE_REFIDS"); refIDs != nil {
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithHiddenRefIDs(refIDs...))
+	}
+	if names := envList("DISCORD_MARKDOWN_ANNOTATIONS"); names != nil {
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithMarkdownAnnotations(names...))
+	}
+	if mentions := envList("DISCORD_ALLOWED_MENTIONS"); mentions != nil {
+		for _, mention := range mentions {
+			if mention != discord.AllowedMentionRoles && mention != discord.AllowedMentionUsers && mention != discord.AllowedMentionEveryone {
+				slog.Error("Invalid DISCORD_ALLOWED_MENTIONS", "mention", mention)
+				os.Exit(1)
+			}
+		}
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithAllowedMentions(mentions...))
+	}
+	if pattern := os.Getenv("DISCORD_TEMPLATES"); pattern != "" {
+		templates, err := transformer.LoadTemplates(pattern)
+		if err != nil {
+			slog.Error("Invalid DISCORD_TEMPLATES", "error", err)
+			os.Exit(1)
+		}
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithTemplates(templates))
+	}
+	if value := os.Getenv("DISCORD_LAYOUT"); value != "" {
+		layout, err := transformer.ParseLayout(value)
+		if err != nil {
+			slog.Error("Invalid DISCORD_LAYOUT", "error", err)
+			os.Exit(1)
+		}
+		webhookCfg.transformOpts = append(webhookCfg.transformOpts, transformer.WithLayout(layout))
+	}
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	router.HandleFunc("GET /ready", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Prometheus metrics endpoint
+	router.Handle("GET /metrics", promhttp.Handler())
+
+	router.HandleFunc("POST /webhook", newWebhookHandler(sender, webhookCfg))
 
 	slog.Info("Server starting", "port", port)
 	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), router); err != nil {
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+	"github.com/pretty-discord-alerts/pkg/discord/discordtest"
+)
+
+const testPayload = `{
+	"receiver": "discord",
+	"status": "firing",
+	"externalURL": "https://monitoring.example.com",
+	"alerts": [{
+		"status": "firing",
+		"labels": {"alertname": "TestAlert", "severity": "critical"},
+		"annotations": {"summary": "Notification test"},
+		"startsAt": "2026-02-02T12:00:00Z",
+		"endsAt": "0001-01-01T00:00:00Z"
+	}]
+}`
+
+// postWebhook sends a Grafana payload to the webhook handler
+func postWebhook(t *testing.T, handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestWebhookHandler_ForwardsToDiscord(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	handler := newWebhookHandler(discord.NewWebhook(server.WebhookURL()), webhookConfig{})
+	rec := postWebhook(t, handler, testPayload)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(messages))
+	}
+	if title := messages[0].Embeds[0].Title; title != "🔥 Critical Alert Firing" {
+		t.Errorf("title = %q, want %q", title, "🔥 Critical Alert Firing")
+	}
+}
+
+func TestWebhookHandler_RetriesRateLimits(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+	server.Enqueue(discordtest.RateLimited(10 * time.Millisecond))
+
+	handler := newWebhookHandler(discord.NewWebhook(server.WebhookURL()), webhookConfig{})
+	rec := postWebhook(t, handler, testPayload)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(server.Messages()) != 1 {
+		t.Errorf("messages = %d, want 1", len(server.Messages()))
+	}
+}
+
+func TestWebhookHandler_DiscordError(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+	server.Enqueue(discordtest.UnknownWebhook())
+
+	handler := newWebhookHandler(discord.NewWebhook(server.WebhookURL()), webhookConfig{})
+	rec := postWebhook(t, handler, testPayload)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestWebhookHandler_InvalidBody(t *testing.T) {
+	server := discordtest.NewServer()
+	defer server.Close()
+
+	handler := newWebhookHandler(discord.NewWebhook(server.WebhookURL()), webhookConfig{})
+	rec := postWebhook(t, handler, "not json")
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if server.Requests() != 0 {
+		t.Errorf("requests = %d, want 0", server.Requests())
+	}
+}
+
+func TestNewSender_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{
+			name: "thread ID with forum threads",
+			env: map[string]string{
+				"DISCORD_WEBHOOK_URL":   "https://discord.com/api/webhooks/123/abc",
+				"DISCORD_THREAD_ID":     "456",
+				"DISCORD_FORUM_THREADS": "true",
+			},
+		},
+		{
+			name: "bot with forum threads",
+			env: map[string]string{
+				"DISCORD_BOT_TOKEN":     "token",
+				"DISCORD_CHANNEL_ID":    "789",
+				"DISCORD_FORUM_THREADS": "true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"DISCORD_BOT_TOKEN", "DISCORD_CHANNEL_ID", "DISCORD_WEBHOOK_URL", "DISCORD_THREAD_ID", "DISCORD_FORUM_THREADS"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if _, err := newSender(nil); err == nil {
+				t.Error("newSender() error = nil, want error")
+			}
+		})
+	}
+}
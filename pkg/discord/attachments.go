@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// File is a file uploaded along with a message
+type File struct {
+	Name        string
+	ContentType string
+	Description string
+	Data        []byte
+}
+
+// Attachment describes an uploaded file in the message payload. For new
+// uploads the ID is the index of the file in Message.Files; the remaining
+// fields are filled in by Discord on sent messages.
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// AttachmentURL returns the URL referencing an uploaded file by name, for use
+// in embed images and thumbnails
+func AttachmentURL(name string) string {
+	return "attachment://" + name
+}
+
+// encodeMessage encodes a message as the request body, using a multipart form
+// when it carries files. It returns the body and its content type.
+func encodeMessage(msg Message) ([]byte, string, error) {
+	if len(msg.Files) == 0 {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal message: %w", err)
+		}
+		return payload, "application/json", nil
+	}
+
+	if len(msg.Attachments) == 0 {
+		for i, file := range msg.Files {
+			msg.Attachments = append(msg.Attachments, Attachment{
+				ID:          strconv.Itoa(i),
+				Filename:    file.Name,
+				Description: file.Description,
+			})
+		}
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode message: %w", err)
+	}
+	if _, err := part.Write(payload); err != nil {
+		return nil, "", fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	for i, file := range msg.Files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, escapeQuotes(file.Name)))
+		header.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode file %q: %w", file.Name, err)
+		}
+		if _, err := part.Write(file.Data); err != nil {
+			return nil, "", fmt.Errorf("failed to encode file %q: %w", file.Name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes a file name for a Content-Disposition header
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEncodeMessage_JSON(t *testing.T) {
+	body, contentType, err := encodeMessage(Message{Content: "Test"})
+	if err != nil {
+		t.Fatalf("encodeMessage() error = %v", err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("content type = %q, want %q", contentType, "application/json")
+	}
+	if !strings.Contains(string(body), `"content":"Test"`) {
+		t.Errorf("body = %s, want JSON message", body)
+	}
+}
+
+func TestEncodeMessage_Multipart(t *testing.T) {
+	msg := Message{
+		Content: "Test",
+		Embeds: []Embed{
+			{
+				Title: "Panel",
+				Image: &EmbedImage{URL: AttachmentURL("panel.png")},
+			},
+		},
+		Files: []File{
+			{Name: "payload.json", ContentType: "application/json", Data: []byte(`{"status":"firing"}`)},
+			{Name: "panel.png", Description: "Rendered panel", Data: []byte{0x89, 'P', 'N', 'G'}},
+		},
+	}
+
+	body, contentType, err := encodeMessage(msg)
+	if err != nil {
+		t.Fatalf("encodeMessage() error = %v", err)
+	}
+
+	parts := readMultipart(t, contentType, body)
+
+	var payload Message
+	if err := json.Unmarshal([]byte(parts["payload_json"]), &payload); err != nil {
+		t.Fatalf("Failed to decode payload_json: %v", err)
+	}
+	if payload.Embeds[0].Image.URL != "attachment://panel.png" {
+		t.Errorf("image URL = %q, want %q", payload.Embeds[0].Image.URL, "attachment://panel.png")
+	}
+	if len(payload.Attachments) != 2 {
+		t.Fatalf("attachments = %d, want 2", len(payload.Attachments))
+	}
+	if a := payload.Attachments[1]; a.ID != "1" || a.Filename != "panel.png" || a.Description != "Rendered panel" {
+		t.Errorf("attachment = %+v, want id 1 for panel.png with description", a)
+	}
+
+	if parts["files[0]"] != `{"status":"firing"}` {
+		t.Errorf("files[0] = %q, want payload JSON", parts["files[0]"])
+	}
+	if parts["files[1]"] != "\x89PNG" {
+		t.Errorf("files[1] = %q, want PNG data", parts["files[1]"])
+	}
+}
+
+func TestWebhook_Send_Files(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/form-data" {
+			t.Errorf("Expected multipart/form-data, got %s", r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Failed to parse multipart form: %v", err)
+		}
+		if _, _, err := r.FormFile("files[0]"); err != nil {
+			t.Errorf("Expected files[0] in form: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	msg := Message{
+		Content: "Test",
+		Files:   []File{{Name: "payload.json", Data: []byte("{}")}},
+	}
+	if err := webhook.Send(msg); err != nil {
+		t.Errorf("Send() error = %v, want nil", err)
+	}
+}
+
+// readMultipart returns the contents of each multipart form part by name
+func readMultipart(t *testing.T, contentType string, body []byte) map[string]string {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("Failed to parse content type %q: %v", contentType, err)
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		parts[part.FormName()] = string(data)
+	}
+	return parts
+}
@@ -0,0 +1,122 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Sender delivers messages to Discord, either through a webhook or as a bot
+type Sender interface {
+	SendContext(ctx context.Context, msg Message) error
+	SendSplit(ctx context.Context, msg Message) error
+}
+
+var (
+	_ Sender = (*Webhook)(nil)
+	_ Sender = (*Bot)(nil)
+)
+
+// Bot represents a Discord client posting to a channel through the bot REST
+// API. Unlike webhooks, bots can pin messages, add reactions and use
+// interactive components.
+type Bot struct {
+	ChannelID string
+	*client
+}
+
+// NewBot creates a new Discord bot client posting to the given channel
+func NewBot(token, channelID string, opts ...Option) *Bot {
+	c := newClient(opts)
+	c.authorization = "Bot " + token
+	return &Bot{ChannelID: channelID, client: c}
+}
+
+// Send sends a message to the bot's channel
+func (b *Bot) Send(msg Message) error {
+	return b.SendContext(context.Background(), msg)
+}
+
+// SendContext sends a message to the bot's channel, giving up when ctx is done
+func (b *Bot) SendContext(ctx context.Context, msg Message) error {
+	_, err := b.CreateMessage(ctx, msg)
+	return err
+}
+
+// CreateMessage sends a message to the bot's channel and returns the message
+// created by Discord. Webhook-only settings such as the username, avatar and
+// thread name are ignored.
+func (b *Bot) CreateMessage(ctx context.Context, msg Message) (*SentMessage, error) {
+	msg.Username = ""
+	msg.AvatarURL = ""
+	msg.ThreadName = ""
+
+	payload, contentType, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := b.do(ctx, http.MethodPost, b.endpoint("/messages"), payload, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, body)
+	}
+
+	var sent SentMessage
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	return &sent, nil
+}
+
+// SendSplit splits the message with Split and sends every part in order
+func (b *Bot) SendSplit(ctx context.Context, msg Message) error {
+	parts := msg.Split()
+	for i, part := range parts {
+		if err := b.SendContext(ctx, part); err != nil {
+			if len(parts) == 1 {
+				return err
+			}
+			return fmt.Errorf("part %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+	return nil
+}
+
+// PinMessage pins a message in the bot's channel
+func (b *Bot) PinMessage(ctx context.Context, messageID string) error {
+	endpoint := b.endpoint("/pins/" + url.PathEscape(messageID))
+	return b.expectNoContent(ctx, http.MethodPut, endpoint, "failed to pin message")
+}
+
+// AddReaction reacts to a message in the bot's channel with a unicode emoji or
+// a custom emoji in name:id form
+func (b *Bot) AddReaction(ctx context.Context, messageID, emoji string) error {
+	endpoint := b.endpoint("/messages/" + url.PathEscape(messageID) + "/reactions/" + url.PathEscape(emoji) + "/@me")
+	return b.expectNoContent(ctx, http.MethodPut, endpoint, "failed to add reaction")
+}
+
+// expectNoContent performs a request without body that Discord answers with 204
+func (b *Bot) expectNoContent(ctx context.Context, method, endpoint, failure string) error {
+	resp, body, err := b.do(ctx, method, endpoint, nil, "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", failure, err)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp, body)
+	}
+
+	return nil
+}
+
+// endpoint builds a URL below the bot's channel
+func (b *Bot) endpoint(path string) string {
+	return b.apiBase + "/channels/" + url.PathEscape(b.ChannelID) + path
+}
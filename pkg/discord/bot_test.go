@@ -0,0 +1,101 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBot_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v10/channels/789/messages" {
+			t.Errorf("Expected path /api/v10/channels/789/messages, got %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bot secret-token" {
+			t.Errorf("Expected Authorization Bot secret-token, got %s", auth)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if _, exists := body["username"]; exists {
+			t.Error("Expected webhook-only username to be omitted")
+		}
+
+		_, _ = w.Write([]byte(`{"id": "1001", "channel_id": "789"}`))
+	}))
+	defer server.Close()
+
+	bot := NewBot("secret-token", "789", WithAPIBase(server.URL+"/api/v10/"))
+	if err := bot.Send(Message{Username: "Grafana", Content: "Test"}); err != nil {
+		t.Errorf("Send() error = %v, want nil", err)
+	}
+}
+
+func TestBot_CreateMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "1001", "channel_id": "789", "content": "Test"}`))
+	}))
+	defer server.Close()
+
+	bot := NewBot("secret-token", "789", WithAPIBase(server.URL))
+	sent, err := bot.CreateMessage(context.Background(), Message{Content: "Test"})
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v, want nil", err)
+	}
+	if sent.ID != "1001" || sent.ChannelID != "789" {
+		t.Errorf("CreateMessage() = %+v, want message 1001 in channel 789", sent)
+	}
+}
+
+func TestBot_Send_MissingAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Missing Access", "code": 50001}`))
+	}))
+	defer server.Close()
+
+	bot := NewBot("secret-token", "789", WithAPIBase(server.URL))
+	err := bot.Send(Message{Content: "Test"})
+
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != ErrCodeMissingAccess {
+		t.Errorf("Send() error = %v, want missing access *APIError", err)
+	}
+}
+
+func TestBot_PinAndReact(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT request, got %s", r.Method)
+		}
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	bot := NewBot("secret-token", "789", WithAPIBase(server.URL))
+	if err := bot.PinMessage(context.Background(), "1001"); err != nil {
+		t.Fatalf("PinMessage() error = %v, want nil", err)
+	}
+	if err := bot.AddReaction(context.Background(), "1001", "🔥"); err != nil {
+		t.Fatalf("AddReaction() error = %v, want nil", err)
+	}
+
+	want := []string{
+		"/channels/789/pins/1001",
+		"/channels/789/messages/1001/reactions/%F0%9F%94%A5/@me",
+	}
+	for i := range want {
+		if i >= len(paths) || paths[i] != want[i] {
+			t.Errorf("request %d path = %v, want %q", i, paths, want[i])
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/metrics"
+)
+
+const (
+	// DefaultAPIBase is the base URL of Discord's REST API
+	DefaultAPIBase = "https://discord.com/api/v10"
+	// maxRateLimitRetries is how many times a rate limited request is retried
+	maxRateLimitRetries = 5
+	// DefaultTimeout bounds a single request to Discord unless set with
+	// WithTimeout
+	DefaultTimeout = 10 * time.Second
+	// defaultUserAgent identifies the service to Discord
+	defaultUserAgent = "pretty-discord-alerts"
+)
+
+// client performs requests against Discord, waiting for rate limits and
+// retrying transient failures. It is shared by the webhook and bot senders.
+type client struct {
+	// Retry controls how transient failures are retried
+	Retry RetryPolicy
+
+	httpClient    *http.Client
+	timeout       time.Duration
+	userAgent     string
+	authorization string
+	apiBase       string
+	limiter       *rateLimiter
+}
+
+// Option configures a Discord client
+type Option func(*client)
+
+// WithHTTPClient sets the HTTP client used to call Discord
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of each request to Discord; zero disables it
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent to Discord
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets how transient failures are retried
+func WithRetryPolicy(retry RetryPolicy) Option {
+	return func(c *client) {
+		c.Retry = retry
+	}
+}
+
+// WithAPIBase sets the base URL of Discord's REST API, e.g. to point at a fake
+// Discord. Webhook URLs on official Discord hosts are rerouted to it.
+func WithAPIBase(apiBase string) Option {
+	return func(c *client) {
+		c.apiBase = strings.TrimSuffix(apiBase, "/")
+	}
+}
+
+// newClient creates a client with the default settings and applies opts
+func newClient(opts []Option) *client {
+	c := &client{
+		Retry:      DefaultRetryPolicy(),
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		userAgent:  defaultUserAgent,
+		apiBase:    DefaultAPIBase,
+		limiter:    newRateLimiter(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// defaultClient is shared by the webhooks built without NewWebhook
+var defaultClient = sync.OnceValue(func() *client { return newClient(nil) })
+
+// do performs a request against Discord, waiting for rate limits and retrying
+// transient failures. It returns the final response along with its body.
+func (c *client) do(ctx context.Context, method, endpoint string, body []byte, contentType string) (*http.Response, []byte, error) {
+	// Rate limits apply per resource, independent of query parameters
+	route, _, _ := strings.Cut(method+" "+endpoint, "?")
+	attempt := 1
+	rateLimited := 0
+	for {
+		if err := c.limiter.wait(ctx, route); err != nil {
+			return nil, nil, err
+		}
+
+		resp, respBody, err := c.attempt(ctx, method, endpoint, body, contentType)
+		if err != nil {
+			if ctx.Err() == nil && c.Retry.canRetry(attempt) && c.Retry.shouldRetryError(err) {
+				metrics.RecordDiscordRetry("network_error")
+				if err := sleep(ctx, c.Retry.backoff(attempt)); err != nil {
+					return nil, nil, err
+				}
+				attempt++
+				continue
+			}
+			return nil, nil, err
+		}
+
+		now := time.Now()
+		c.limiter.update(route, resp.Header, now)
+
+		if resp.StatusCode == http.StatusTooManyRequests && rateLimited < maxRateLimitRetries {
+			// Rate limit retries don't count against the retry policy
+			metrics.RecordDiscordRetry("rate_limited")
+			c.limiter.limit(route, retryAfter(resp.Header), isGlobal(resp.Header), now)
+			rateLimited++
+			continue
+		}
+
+		if c.Retry.shouldRetryStatus(resp.StatusCode) && c.Retry.canRetry(attempt) {
+			metrics.RecordDiscordRetry("server_error")
+			if err := sleep(ctx, c.Retry.backoff(attempt)); err != nil {
+				return nil, nil, err
+			}
+			attempt++
+			continue
+		}
+
+		return resp, respBody, nil
+	}
+}
+
+// attempt performs a single HTTP request bounded by the configured timeout
+func (c *client) attempt(ctx context.Context, method, endpoint string, body []byte, contentType string) (*http.Response, []byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Keep the webhook token out of error messages and logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = RedactURL(urlErr.URL)
+		}
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
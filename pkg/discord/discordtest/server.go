@@ -0,0 +1,465 @@
+// Package discordtest provides a fake Discord API server for tests.
+package discordtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+)
+
+// Identifiers of the fake webhook and its channel
+const (
+	WebhookID    = "100000000000000001"
+	WebhookToken = "test-token"
+	GuildID      = "100000000000000002"
+	ChannelID    = "100000000000000003"
+)
+
+// ReceivedMessage is a message stored by the fake server
+type ReceivedMessage struct {
+	discord.Message
+	// ID is the snowflake assigned by the server
+	ID string
+	// ChannelID is the channel or thread the message was posted to
+	ChannelID string
+	// Edited reports whether the message was edited after it was created
+	Edited bool
+}
+
+// Response is a scripted response returned instead of handling a request
+type Response struct {
+	Status int
+	// RetryAfter is sent as the Retry-After header and retry_after field of a 429
+	RetryAfter time.Duration
+	// Code and Message form Discord's JSON error body
+	Code    int
+	Message string
+}
+
+// RateLimited returns a 429 response asking the client to wait for retryAfter
+func RateLimited(retryAfter time.Duration) Response {
+	return Response{
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+		Message:    "You are being rate limited.",
+	}
+}
+
+// ServerError returns a Discord-side failure with the given 5xx status
+func ServerError(status int) Response {
+	return Response{Status: status, Message: http.StatusText(status)}
+}
+
+// UnknownWebhook returns the 404 Discord sends for a deleted webhook
+func UnknownWebhook() Response {
+	return Response{Status: http.StatusNotFound, Code: discord.ErrCodeUnknownWebhook, Message: "Unknown Webhook"}
+}
+
+// Server is a fake Discord API that records the messages it receives. It
+// serves a single webhook (WebhookURL) and bot channel (ChannelID), validates
+// messages against Discord's limits, and supports wait=true, threads, forum
+// posts, editing and deleting messages.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	nextID    int64
+	messages  []*ReceivedMessage
+	scripted  []Response
+	requests  int
+	botTokens map[string]bool
+}
+
+// NewServer starts a fake Discord server. Close it when done.
+func NewServer() *Server {
+	s := &Server{nextID: 200000000000000000, botTokens: make(map[string]bool)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/webhooks/{id}/{token}", s.webhook(s.handleGetWebhook))
+	mux.HandleFunc("POST /api/webhooks/{id}/{token}", s.webhook(s.handleExecute))
+	mux.HandleFunc("PATCH /api/webhooks/{id}/{token}/messages/{message}", s.webhook(s.handleEdit))
+	mux.HandleFunc("DELETE /api/webhooks/{id}/{token}/messages/{message}", s.webhook(s.handleDelete))
+	mux.HandleFunc("POST /api/channels/{channel}/messages", s.bot(s.handleCreateMessage))
+
+	s.Server = httptest.NewServer(s.script(mux))
+	return s
+}
+
+// APIBase returns the base URL of the fake API, for discord.WithAPIBase
+func (s *Server) APIBase() string {
+	return s.URL + "/api"
+}
+
+// WebhookURL returns the URL of the fake webhook
+func (s *Server) WebhookURL() string {
+	return s.APIBase() + "/webhooks/" + WebhookID + "/" + WebhookToken
+}
+
+// AllowBotToken accepts the token for bot requests to ChannelID
+func (s *Server) AllowBotToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.botTokens[token] = true
+}
+
+// Enqueue scripts responses returned, in order, for the next requests instead
+// of handling them
+func (s *Server) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripted = append(s.scripted, responses...)
+}
+
+// Messages returns the messages that have not been deleted, in the order
+// they were created
+func (s *Server) Messages() []ReceivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]ReceivedMessage, len(s.messages))
+	for i, msg := range s.messages {
+		messages[i] = *msg
+	}
+	return messages
+}
+
+// Requests returns the number of requests received, including scripted ones
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Reset forgets all messages, scripted responses and request counts
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+	s.scripted = nil
+	s.requests = 0
+}
+
+// script counts requests and answers them with scripted responses while any
+// are queued
+func (s *Server) script(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		var resp *Response
+		if len(s.scripted) > 0 {
+			resp = &s.scripted[0]
+			s.scripted = s.scripted[1:]
+		}
+		s.mu.Unlock()
+
+		if resp == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if resp.RetryAfter > 0 {
+			seconds := strconv.FormatFloat(resp.RetryAfter.Seconds(), 'f', -1, 64)
+			w.Header().Set("Retry-After", seconds)
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset-After", seconds)
+			w.Header().Set("X-RateLimit-Bucket", "discordtest")
+		}
+		writeJSON(w, resp.Status, map[string]any{
+			"code":        resp.Code,
+			"message":     resp.Message,
+			"retry_after": resp.RetryAfter.Seconds(),
+		})
+	})
+}
+
+// webhook rejects requests for any webhook but the fake one
+func (s *Server) webhook(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != WebhookID || r.PathValue("token") != WebhookToken {
+			writeError(w, http.StatusNotFound, discord.ErrCodeUnknownWebhook, "Unknown Webhook", nil)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// bot rejects requests without an allowed bot token or for another channel
+func (s *Server) bot(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bot ")
+		s.mu.Lock()
+		allowed := ok && s.botTokens[token]
+		s.mu.Unlock()
+		if !allowed {
+			writeError(w, http.StatusUnauthorized, 0, "401: Unauthorized", nil)
+			return
+		}
+		if r.PathValue("channel") != ChannelID {
+			writeError(w, http.StatusNotFound, discord.ErrCodeUnknownChannel, "Unknown Channel", nil)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, discord.WebhookInfo{
+		ID:        WebhookID,
+		Type:      1,
+		Name:      "discordtest",
+		GuildID:   GuildID,
+		ChannelID: ChannelID,
+	})
+}
+
+func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
+	msg, ok := decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	channelID := ChannelID
+	if threadID := r.URL.Query().Get("thread_id"); threadID != "" {
+		if !s.knownChannel(threadID) {
+			writeError(w, http.StatusBadRequest, discord.ErrCodeUnknownChannel, "Unknown Channel", nil)
+			return
+		}
+		channelID = threadID
+	}
+
+	s.mu.Lock()
+	id := s.newID()
+	if msg.ThreadName != "" {
+		// A forum post creates a thread whose ID is also its starter message ID
+		channelID = id
+	}
+	received := &ReceivedMessage{Message: msg, ID: id, ChannelID: channelID}
+	s.messages = append(s.messages, received)
+	sent := sentMessage(received)
+	s.mu.Unlock()
+
+	if r.URL.Query().Get("wait") != "true" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, sent)
+}
+
+func (s *Server) handleEdit(w http.ResponseWriter, r *http.Request) {
+	msg, ok := decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	received := s.find(r.PathValue("message"), r.URL.Query().Get("thread_id"))
+	if received == nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, discord.ErrCodeUnknownMessage, "Unknown Message", nil)
+		return
+	}
+	received.Message = msg
+	received.Edited = true
+	sent := sentMessage(received)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, sent)
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	received := s.find(r.PathValue("message"), r.URL.Query().Get("thread_id"))
+	if received == nil {
+		writeError(w, http.StatusNotFound, discord.ErrCodeUnknownMessage, "Unknown Message", nil)
+		return
+	}
+	for i, msg := range s.messages {
+		if msg == received {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCreateMessage(w http.ResponseWriter, r *http.Request) {
+	msg, ok := decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	received := &ReceivedMessage{Message: msg, ID: s.newID(), ChannelID: ChannelID}
+	s.messages = append(s.messages, received)
+	sent := sentMessage(received)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, sent)
+}
+
+// find returns the message with the given ID in the channel or thread.
+// The caller must hold s.mu.
+func (s *Server) find(id, threadID string) *ReceivedMessage {
+	channelID := ChannelID
+	if threadID != "" {
+		channelID = threadID
+	}
+	for _, msg := range s.messages {
+		if msg.ID == id && msg.ChannelID == channelID {
+			return msg
+		}
+	}
+	return nil
+}
+
+// knownChannel reports whether a thread has been created by a forum post
+func (s *Server) knownChannel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range s.messages {
+		if msg.ChannelID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// newID returns a new snowflake. The caller must hold s.mu.
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.FormatInt(s.nextID, 10)
+}
+
+// sentMessage builds the message object Discord returns for a stored message
+func sentMessage(msg *ReceivedMessage) discord.SentMessage {
+	sent := discord.SentMessage{
+		ID:          msg.ID,
+		ChannelID:   msg.ChannelID,
+		WebhookID:   WebhookID,
+		Content:     msg.Content,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Embeds:      msg.Embeds,
+		Attachments: msg.Attachments,
+		Flags:       msg.Flags,
+	}
+	if msg.Edited {
+		sent.EditedTimestamp = sent.Timestamp
+	}
+	return sent
+}
+
+// decodeMessage decodes a JSON or multipart message body and validates it
+// against Discord's limits, writing an error response if it is invalid
+func decodeMessage(w http.ResponseWriter, r *http.Request) (discord.Message, bool) {
+	var msg discord.Message
+	if err := readMessage(r, &msg); err != nil {
+		writeError(w, http.StatusBadRequest, 50109, "The request body contains invalid JSON.", nil)
+		return msg, false
+	}
+
+	if err := msg.Validate(); err != nil {
+		var validationErr *discord.ValidationError
+		if errors.As(err, &validationErr) {
+			writeError(w, http.StatusBadRequest, discord.ErrCodeInvalidFormBody, "Invalid Form Body", fieldErrors(validationErr))
+			return msg, false
+		}
+	}
+	if msg.Content == "" && len(msg.Embeds) == 0 && len(msg.Files) == 0 && len(msg.Components) == 0 && msg.Poll == nil {
+		writeError(w, http.StatusBadRequest, 50006, "Cannot send an empty message", nil)
+		return msg, false
+	}
+
+	return msg, true
+}
+
+// readMessage decodes the request body, reading payload_json and files[n]
+// parts of multipart requests
+func readMessage(r *http.Request, msg *discord.Message) error {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	if mediaType != "multipart/form-data" {
+		return json.NewDecoder(r.Body).Decode(msg)
+	}
+
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		switch name := part.FormName(); {
+		case name == "payload_json":
+			if err := json.Unmarshal(data, msg); err != nil {
+				return err
+			}
+		case strings.HasPrefix(name, "files["):
+			msg.Files = append(msg.Files, discord.File{
+				Name:        part.FileName(),
+				ContentType: part.Header.Get("Content-Type"),
+				Data:        data,
+			})
+		}
+	}
+}
+
+// fieldPathIndex matches the index notation of limit error fields
+var fieldPathIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// fieldErrors converts limit errors into Discord's nested error tree
+func fieldErrors(err *discord.ValidationError) map[string]any {
+	tree := make(map[string]any)
+	for _, limitErr := range err.Errors {
+		path := fieldPathIndex.ReplaceAllString(limitErr.Field, ".$1")
+		node := tree
+		for _, key := range strings.Split(path, ".") {
+			child, ok := node[key].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[key] = child
+			}
+			node = child
+		}
+		node["_errors"] = []map[string]string{{
+			"code":    "BASE_TYPE_MAX_LENGTH",
+			"message": fmt.Sprintf("Must be %d or fewer in length.", limitErr.Limit),
+		}}
+	}
+	return tree
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string, errs map[string]any) {
+	body := map[string]any{"code": code, "message": message}
+	if errs != nil {
+		body["errors"] = errs
+	}
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,201 @@
+package discordtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+)
+
+func TestServer_Send(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	if err := webhook.Send(discord.Message{Username: "Grafana", Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(messages))
+	}
+	if messages[0].Username != "Grafana" || messages[0].ChannelID != ChannelID {
+		t.Errorf("message = %+v, want Grafana message in channel %s", messages[0], ChannelID)
+	}
+}
+
+func TestServer_WebhookURLIsValid(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	if _, err := discord.ParseWebhookURL(server.WebhookURL(), server.APIBase()); err != nil {
+		t.Errorf("ParseWebhookURL() error = %v, want nil", err)
+	}
+
+	info, err := discord.NewWebhook(server.WebhookURL()).Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v, want nil", err)
+	}
+	if info.ChannelID != ChannelID || info.GuildID != GuildID {
+		t.Errorf("Info() = %+v, want channel %s in guild %s", info, ChannelID, GuildID)
+	}
+}
+
+func TestServer_EditAndDelete(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	sent, err := webhook.SendWait(context.Background(), discord.Message{Content: "Firing"})
+	if err != nil {
+		t.Fatalf("SendWait() error = %v, want nil", err)
+	}
+
+	if _, err := webhook.EditMessage(sent.ID, discord.Message{Content: "Resolved"}); err != nil {
+		t.Fatalf("EditMessage() error = %v, want nil", err)
+	}
+	messages := server.Messages()
+	if messages[0].Content != "Resolved" || !messages[0].Edited {
+		t.Errorf("message = %+v, want edited to Resolved", messages[0])
+	}
+
+	if err := webhook.DeleteMessage(sent.ID); err != nil {
+		t.Fatalf("DeleteMessage() error = %v, want nil", err)
+	}
+	if len(server.Messages()) != 0 {
+		t.Error("message was not deleted")
+	}
+
+	var apiErr *discord.APIError
+	err = webhook.DeleteMessage(sent.ID)
+	if !errors.As(err, &apiErr) || apiErr.Code != discord.ErrCodeUnknownMessage {
+		t.Errorf("DeleteMessage() error = %v, want unknown message", err)
+	}
+}
+
+func TestServer_ForumThreads(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	post, err := webhook.SendWait(context.Background(), discord.Message{Content: "Firing", ThreadName: "HighCPU"})
+	if err != nil {
+		t.Fatalf("SendWait() error = %v, want nil", err)
+	}
+	if post.ChannelID == ChannelID {
+		t.Fatal("forum post should create a new thread")
+	}
+	if post.ID != post.ChannelID {
+		t.Errorf("thread ID = %s, want the starter message ID %s", post.ChannelID, post.ID)
+	}
+
+	if err := webhook.Thread(post.ChannelID).Send(discord.Message{Content: "Still firing"}); err != nil {
+		t.Fatalf("Send() to thread error = %v, want nil", err)
+	}
+	if err := webhook.Thread("999").Send(discord.Message{Content: "Lost"}); err == nil {
+		t.Error("Send() to unknown thread error = nil, want error")
+	}
+
+	messages := server.Messages()
+	if len(messages) != 2 || messages[1].ChannelID != post.ChannelID {
+		t.Errorf("messages = %+v, want reply in thread %s", messages, post.ChannelID)
+	}
+}
+
+func TestServer_ValidatesLimits(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	msg := discord.Message{Embeds: []discord.Embed{{Title: strings.Repeat("a", discord.MaxEmbedTitleLength+1)}}}
+
+	var apiErr *discord.APIError
+	err := webhook.Send(msg)
+	if !errors.As(err, &apiErr) || apiErr.Code != discord.ErrCodeInvalidFormBody {
+		t.Fatalf("Send() error = %v, want invalid form body", err)
+	}
+
+	fieldErrs := apiErr.FieldErrors()
+	if len(fieldErrs) != 1 || fieldErrs[0].Path != "embeds.0.title" {
+		t.Errorf("FieldErrors() = %+v, want embeds.0.title", fieldErrs)
+	}
+
+	if err := webhook.Send(discord.Message{}); err == nil {
+		t.Error("Send() of empty message error = nil, want error")
+	}
+}
+
+func TestServer_ScriptedResponses(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.Enqueue(RateLimited(20*time.Millisecond), ServerError(http.StatusBadGateway))
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	webhook.Retry.BaseBackoff = time.Millisecond
+
+	start := time.Now()
+	if err := webhook.Send(discord.Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil after retries", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Send() returned after %v, want it to honor Retry-After", elapsed)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("requests = %d, want 3", server.Requests())
+	}
+	if len(server.Messages()) != 1 {
+		t.Errorf("messages = %d, want 1", len(server.Messages()))
+	}
+
+	server.Enqueue(UnknownWebhook())
+	var apiErr *discord.APIError
+	err := webhook.Send(discord.Message{Content: "Test"})
+	if !errors.As(err, &apiErr) || apiErr.Code != discord.ErrCodeUnknownWebhook {
+		t.Errorf("Send() error = %v, want unknown webhook", err)
+	}
+}
+
+func TestServer_Files(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	webhook := discord.NewWebhook(server.WebhookURL())
+	msg := discord.Message{
+		Content: "Test",
+		Files:   []discord.File{{Name: "payload.json", ContentType: "application/json", Data: []byte("{}")}},
+	}
+	if err := webhook.Send(msg); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	files := server.Messages()[0].Files
+	if len(files) != 1 || files[0].Name != "payload.json" || string(files[0].Data) != "{}" {
+		t.Errorf("files = %+v, want payload.json", files)
+	}
+}
+
+func TestServer_Bot(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AllowBotToken("bot-token")
+
+	bot := discord.NewBot("bot-token", ChannelID, discord.WithAPIBase(server.APIBase()))
+	if err := bot.Send(discord.Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	intruder := discord.NewBot("wrong-token", ChannelID, discord.WithAPIBase(server.APIBase()))
+	if err := intruder.Send(discord.Message{Content: "Test"}); err == nil {
+		t.Error("Send() with wrong token error = nil, want error")
+	}
+
+	if len(server.Messages()) != 1 {
+		t.Errorf("messages = %d, want 1", len(server.Messages()))
+	}
+}
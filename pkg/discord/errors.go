@@ -0,0 +1,104 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Discord JSON error codes
+const (
+	ErrCodeUnknownChannel  = 10003
+	ErrCodeUnknownMessage  = 10008
+	ErrCodeUnknownWebhook  = 10015
+	ErrCodeMissingAccess   = 50001
+	ErrCodeInvalidToken    = 50027
+	ErrCodeInvalidFormBody = 50035
+)
+
+// APIError is returned when Discord responds with an unexpected status code.
+// Use errors.As to inspect it.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"-"`
+	// Code is Discord's JSON error code, or zero if the body had none
+	Code int `json:"code"`
+	// Message is Discord's description of the error
+	Message string `json:"message"`
+	// Errors holds the nested field errors of an invalid form body
+	Errors json.RawMessage `json:"errors,omitempty"`
+}
+
+// FieldError is a single error reported for a field of the request body
+type FieldError struct {
+	// Path is the dotted path of the field, e.g. "embeds.0.fields.2.value"
+	Path    string
+	Code    string
+	Message string
+}
+
+// newAPIError builds an APIError from a response, parsing the JSON error body
+// when there is one
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{}
+	_ = json.Unmarshal(body, apiErr)
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	if e.Message != "" {
+		msg += fmt.Sprintf(": %s (code %d)", e.Message, e.Code)
+	}
+	for _, fieldErr := range e.FieldErrors() {
+		msg += fmt.Sprintf("; %s: %s", fieldErr.Path, fieldErr.Message)
+	}
+	return msg
+}
+
+// FieldErrors flattens the nested field errors of the response, sorted by path
+func (e *APIError) FieldErrors() []FieldError {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	var tree map[string]json.RawMessage
+	if err := json.Unmarshal(e.Errors, &tree); err != nil {
+		return nil
+	}
+
+	var fieldErrs []FieldError
+	collectFieldErrors(tree, "", &fieldErrs)
+	sort.SliceStable(fieldErrs, func(i, j int) bool {
+		return fieldErrs[i].Path < fieldErrs[j].Path
+	})
+	return fieldErrs
+}
+
+// collectFieldErrors walks Discord's error tree, where each level is keyed by
+// field name or index and leaves are "_errors" lists
+func collectFieldErrors(tree map[string]json.RawMessage, path string, fieldErrs *[]FieldError) {
+	for key, raw := range tree {
+		if key == "_errors" {
+			var errs []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(raw, &errs); err != nil {
+				continue
+			}
+			for _, err := range errs {
+				*fieldErrs = append(*fieldErrs, FieldError{Path: path, Code: err.Code, Message: err.Message})
+			}
+			continue
+		}
+
+		var child map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &child); err != nil {
+			continue
+		}
+		collectFieldErrors(child, strings.TrimPrefix(path+"."+key, "."), fieldErrs)
+	}
+}
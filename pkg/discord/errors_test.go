@@ -0,0 +1,92 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook_Send_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{
+			"code": 50035,
+			"message": "Invalid Form Body",
+			"errors": {
+				"embeds": {
+					"0": {
+						"title": {"_errors": [{"code": "BASE_TYPE_MAX_LENGTH", "message": "Must be 256 or fewer in length."}]},
+						"fields": {"1": {"value": {"_errors": [{"code": "BASE_TYPE_REQUIRED", "message": "This field is required"}]}}}
+					}
+				}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	err := webhook.Send(Message{Content: "Test"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Send() error = %v, want *APIError", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusBadRequest)
+	}
+	if apiErr.Code != ErrCodeInvalidFormBody {
+		t.Errorf("Code = %d, want %d", apiErr.Code, ErrCodeInvalidFormBody)
+	}
+	if apiErr.Message != "Invalid Form Body" {
+		t.Errorf("Message = %q, want %q", apiErr.Message, "Invalid Form Body")
+	}
+
+	fieldErrs := apiErr.FieldErrors()
+	want := []FieldError{
+		{Path: "embeds.0.fields.1.value", Code: "BASE_TYPE_REQUIRED", Message: "This field is required"},
+		{Path: "embeds.0.title", Code: "BASE_TYPE_MAX_LENGTH", Message: "Must be 256 or fewer in length."},
+	}
+	if len(fieldErrs) != len(want) {
+		t.Fatalf("FieldErrors() = %+v, want %+v", fieldErrs, want)
+	}
+	for i := range want {
+		if fieldErrs[i] != want[i] {
+			t.Errorf("FieldErrors()[%d] = %+v, want %+v", i, fieldErrs[i], want[i])
+		}
+	}
+}
+
+func TestWebhook_Send_UnknownWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Unknown Webhook", "code": 10015}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	err := webhook.Send(Message{Content: "Test"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != ErrCodeUnknownWebhook {
+		t.Fatalf("Send() error = %v, want unknown webhook *APIError", err)
+	}
+
+	expectedErr := "unexpected status code: 404: Unknown Webhook (code 10015)"
+	if err.Error() != expectedErr {
+		t.Errorf("Send() error = %q, want %q", err.Error(), expectedErr)
+	}
+}
+
+func TestAPIError_NonJSONBody(t *testing.T) {
+	apiErr := newAPIError(&http.Response{StatusCode: http.StatusBadGateway}, []byte("<html>Bad Gateway</html>"))
+
+	if apiErr.StatusCode != http.StatusBadGateway || apiErr.Code != 0 {
+		t.Errorf("newAPIError() = %+v, want status 502 without code", apiErr)
+	}
+	if apiErr.FieldErrors() != nil {
+		t.Errorf("FieldErrors() = %+v, want nil", apiErr.FieldErrors())
+	}
+}
@@ -0,0 +1,217 @@
+package discord
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord message limits
+const (
+	MaxContentLength          = 2000
+	MaxUsernameLength         = 80
+	MaxThreadNameLength       = 100
+	MaxEmbeds                 = 10
+	MaxEmbedTitleLength       = 256
+	MaxEmbedDescriptionLength = 4096
+	MaxEmbedFields            = 25
+	MaxFieldNameLength        = 256
+	MaxFieldValueLength       = 1024
+	MaxFooterTextLength       = 2048
+	MaxAuthorNameLength       = 256
+	// MaxEmbedsTotalLength caps the combined title, description, field,
+	// footer and author text of all embeds in a message
+	MaxEmbedsTotalLength = 6000
+)
+
+// ellipsis marks truncated text
+const ellipsis = "…"
+
+// LimitError reports a part of a message that exceeds a Discord limit
+type LimitError struct {
+	// Field is the path of the offending part, e.g. "embeds[0].fields[2].value"
+	Field  string
+	Limit  int
+	Length int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: length %d exceeds limit of %d", e.Field, e.Length, e.Limit)
+}
+
+// ValidationError lists every limit a message exceeds
+type ValidationError struct {
+	Errors []*LimitError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "message exceeds Discord limits: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes the individual limit errors to errors.Is and errors.As
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate checks the message against Discord's limits. It returns a
+// *ValidationError listing every violation, or nil if the message is valid.
+func (m Message) Validate() error {
+	var errs []*LimitError
+	check := func(field string, length, limit int) {
+		if length > limit {
+			errs = append(errs, &LimitError{Field: field, Limit: limit, Length: length})
+		}
+	}
+
+	check("content", runeCount(m.Content), MaxContentLength)
+	check("username", runeCount(m.Username), MaxUsernameLength)
+	check("thread_name", runeCount(m.ThreadName), MaxThreadNameLength)
+	check("embeds", len(m.Embeds), MaxEmbeds)
+
+	total := 0
+	for i, embed := range m.Embeds {
+		prefix := fmt.Sprintf("embeds[%d]", i)
+		check(prefix+".title", runeCount(embed.Title), MaxEmbedTitleLength)
+		check(prefix+".description", runeCount(embed.Description), MaxEmbedDescriptionLength)
+		check(prefix+".fields", len(embed.Fields), MaxEmbedFields)
+		for j, field := range embed.Fields {
+			check(fmt.Sprintf("%s.fields[%d].name", prefix, j), runeCount(field.Name), MaxFieldNameLength)
+			check(fmt.Sprintf("%s.fields[%d].value", prefix, j), runeCount(field.Value), MaxFieldValueLength)
+		}
+		if embed.Footer != nil {
+			check(prefix+".footer.text", runeCount(embed.Footer.Text), MaxFooterTextLength)
+		}
+		if embed.Author != nil {
+			check(prefix+".author.name", runeCount(embed.Author.Name), MaxAuthorNameLength)
+		}
+		total += embed.length()
+	}
+	check("embeds.total", total, MaxEmbedsTotalLength)
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Truncate returns a copy of the message shortened to fit Discord's limits.
+// Text is cut at rune boundaries and marked with an ellipsis; fields and
+// embeds beyond the maximum count are dropped.
+func (m Message) Truncate() Message {
+	m.Content = truncate(m.Content, MaxContentLength)
+	m.Username = truncate(m.Username, MaxUsernameLength)
+	m.ThreadName = truncate(m.ThreadName, MaxThreadNameLength)
+
+	if len(m.Embeds) > MaxEmbeds {
+		m.Embeds = m.Embeds[:MaxEmbeds]
+	}
+	m.Embeds = slices.Clone(m.Embeds)
+	for i := range m.Embeds {
+		m.Embeds[i] = m.Embeds[i].truncate()
+	}
+
+	// Shrink field values and descriptions, starting from the last embed,
+	// until the combined length fits
+	excess := -MaxEmbedsTotalLength
+	for _, embed := range m.Embeds {
+		excess += embed.length()
+	}
+	for i := len(m.Embeds) - 1; i >= 0 && excess > 0; i-- {
+		embed := &m.Embeds[i]
+		for j := len(embed.Fields) - 1; j >= 0 && excess > 0; j-- {
+			excess -= shrink(&embed.Fields[j].Value, excess)
+		}
+		if excess > 0 {
+			excess -= shrink(&embed.Description, excess)
+		}
+	}
+
+	return m
+}
+
+// truncate returns a copy of the embed with each part shortened to its limit
+func (e Embed) truncate() Embed {
+	if len(e.Fields) > MaxEmbedFields {
+		e.Fields = e.Fields[:MaxEmbedFields]
+	}
+	return e.truncateText()
+}
+
+// truncateText returns a copy of the embed with each text shortened to its
+// limit, keeping every field
+func (e Embed) truncateText() Embed {
+	e.Title = truncate(e.Title, MaxEmbedTitleLength)
+	e.Description = truncate(e.Description, MaxEmbedDescriptionLength)
+
+	e.Fields = slices.Clone(e.Fields)
+	for i := range e.Fields {
+		e.Fields[i].Name = truncate(e.Fields[i].Name, MaxFieldNameLength)
+		e.Fields[i].Value = truncate(e.Fields[i].Value, MaxFieldValueLength)
+	}
+
+	if e.Footer != nil {
+		footer := *e.Footer
+		footer.Text = truncate(footer.Text, MaxFooterTextLength)
+		e.Footer = &footer
+	}
+	if e.Author != nil {
+		author := *e.Author
+		author.Name = truncate(author.Name, MaxAuthorNameLength)
+		e.Author = &author
+	}
+
+	return e
+}
+
+// length returns the number of characters the embed counts towards the
+// combined embed limit
+func (e Embed) length() int {
+	n := runeCount(e.Title) + runeCount(e.Description)
+	for _, field := range e.Fields {
+		n += runeCount(field.Name) + runeCount(field.Value)
+	}
+	if e.Footer != nil {
+		n += runeCount(e.Footer.Text)
+	}
+	if e.Author != nil {
+		n += runeCount(e.Author.Name)
+	}
+	return n
+}
+
+// shrink shortens s by up to n characters, keeping at least the ellipsis, and
+// returns how many characters were removed
+func shrink(s *string, n int) int {
+	length := runeCount(*s)
+	if length <= 1 {
+		return 0
+	}
+	target := max(length-n, 1)
+	*s = truncate(*s, target)
+	return length - target
+}
+
+// truncate shortens s to at most limit runes, ending it with an ellipsis if cut
+func truncate(s string, limit int) string {
+	if runeCount(s) <= limit {
+		return s
+	}
+	if limit <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:limit-1]) + ellipsis
+}
+
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
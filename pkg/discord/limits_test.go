@@ -0,0 +1,178 @@
+package discord
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        Message
+		wantFields []string
+	}{
+		{
+			name: "valid message",
+			msg: Message{
+				Content: "Test",
+				Embeds:  []Embed{{Title: "Title", Fields: []EmbedField{{Name: "Name", Value: "Value"}}}},
+			},
+		},
+		{
+			name: "long title",
+			msg: Message{
+				Embeds: []Embed{{Title: strings.Repeat("a", MaxEmbedTitleLength+1)}},
+			},
+			wantFields: []string{"embeds[0].title"},
+		},
+		{
+			name: "long field value",
+			msg: Message{
+				Embeds: []Embed{{Fields: []EmbedField{
+					{Name: "ok", Value: "ok"},
+					{Name: "big", Value: strings.Repeat("a", MaxFieldValueLength+1)},
+				}}},
+			},
+			wantFields: []string{"embeds[0].fields[1].value"},
+		},
+		{
+			name: "too many fields",
+			msg: Message{
+				Embeds: []Embed{{Fields: make([]EmbedField, MaxEmbedFields+1)}},
+			},
+			wantFields: []string{"embeds[0].fields"},
+		},
+		{
+			name: "too many embeds",
+			msg: Message{
+				Embeds: make([]Embed, MaxEmbeds+1),
+			},
+			wantFields: []string{"embeds"},
+		},
+		{
+			name: "total embed length",
+			msg: Message{
+				Embeds: []Embed{
+					{Description: strings.Repeat("a", 4000)},
+					{Description: strings.Repeat("a", 4000)},
+				},
+			},
+			wantFields: []string{"embeds.total"},
+		},
+		{
+			name: "multibyte characters count as one",
+			msg: Message{
+				Embeds: []Embed{{Title: strings.Repeat("🔥", MaxEmbedTitleLength)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if len(validationErr.Errors) != len(tt.wantFields) {
+				t.Fatalf("Validate() errors = %v, want fields %v", validationErr.Errors, tt.wantFields)
+			}
+			for i, field := range tt.wantFields {
+				if validationErr.Errors[i].Field != field {
+					t.Errorf("error %d field = %q, want %q", i, validationErr.Errors[i].Field, field)
+				}
+			}
+
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Error("errors.As() did not find a *LimitError")
+			}
+		})
+	}
+}
+
+func TestMessage_Truncate(t *testing.T) {
+	msg := Message{
+		Content: strings.Repeat("c", MaxContentLength+10),
+		Embeds: []Embed{
+			{
+				Title:       strings.Repeat("🔥", MaxEmbedTitleLength+10),
+				Description: strings.Repeat("d", 3000),
+				Fields: []EmbedField{
+					{Name: "Details", Value: strings.Repeat("v", 3000)},
+				},
+				Footer: &EmbedFooter{Text: "Footer"},
+			},
+			{
+				Description: strings.Repeat("d", 3000),
+			},
+		},
+	}
+
+	truncated := msg.Truncate()
+	if err := truncated.Validate(); err != nil {
+		t.Fatalf("Truncate() result is invalid: %v", err)
+	}
+
+	if !strings.HasSuffix(truncated.Content, ellipsis) {
+		t.Error("truncated content should end with an ellipsis")
+	}
+
+	title := truncated.Embeds[0].Title
+	if !utf8.ValidString(title) || utf8.RuneCountInString(title) != MaxEmbedTitleLength {
+		t.Errorf("title length = %d runes, want %d valid runes", utf8.RuneCountInString(title), MaxEmbedTitleLength)
+	}
+
+	if value := truncated.Embeds[0].Fields[0].Value; utf8.RuneCountInString(value) > MaxFieldValueLength {
+		t.Errorf("field value length = %d, want <= %d", utf8.RuneCountInString(value), MaxFieldValueLength)
+	}
+
+	// The original message is left untouched
+	if len(msg.Embeds[0].Fields[0].Value) != 3000 {
+		t.Error("Truncate() modified the original message")
+	}
+}
+
+func TestMessage_Truncate_DropsExtras(t *testing.T) {
+	msg := Message{
+		Embeds: make([]Embed, MaxEmbeds+2),
+	}
+	msg.Embeds[0].Fields = make([]EmbedField, MaxEmbedFields+5)
+
+	truncated := msg.Truncate()
+	if len(truncated.Embeds) != MaxEmbeds {
+		t.Errorf("embeds = %d, want %d", len(truncated.Embeds), MaxEmbeds)
+	}
+	if len(truncated.Embeds[0].Fields) != MaxEmbedFields {
+		t.Errorf("fields = %d, want %d", len(truncated.Embeds[0].Fields), MaxEmbedFields)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		limit int
+		want  string
+	}{
+		{s: "short", limit: 10, want: "short"},
+		{s: "exactly", limit: 7, want: "exactly"},
+		{s: "too long", limit: 5, want: "too …"},
+		{s: "héllo wörld", limit: 6, want: "héllo…"},
+		{s: "anything", limit: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.limit); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+		}
+	}
+}
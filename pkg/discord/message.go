@@ -0,0 +1,181 @@
+package discord
+
+// Message flags that can be set when executing a webhook
+const (
+	// MessageFlagSuppressEmbeds hides link previews in the message
+	MessageFlagSuppressEmbeds = 1 << 2
+	// MessageFlagSuppressNotifications sends the message without push and desktop notifications
+	MessageFlagSuppressNotifications = 1 << 12
+)
+
+// Component types
+const (
+	ComponentTypeActionRow = 1
+	ComponentTypeButton    = 2
+)
+
+// Button styles
+const (
+	ButtonStylePrimary   = 1
+	ButtonStyleSecondary = 2
+	ButtonStyleSuccess   = 3
+	ButtonStyleDanger    = 4
+	ButtonStyleLink      = 5
+)
+
+// Allowed mention types
+const (
+	AllowedMentionRoles    = "roles"
+	AllowedMentionUsers    = "users"
+	AllowedMentionEveryone = "everyone"
+)
+
+// Message represents a Discord message payload
+type Message struct {
+	Username        string           `json:"username,omitempty"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	Content         string           `json:"content,omitempty"`
+	TTS             bool             `json:"tts,omitempty"`
+	Embeds          []Embed          `json:"embeds,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Components      []Component      `json:"components,omitempty"`
+	Flags           int              `json:"flags,omitempty"`
+	// ThreadName creates a new forum post with this name; use Webhook.Thread
+	// to reply into an existing thread instead
+	ThreadName string `json:"thread_name,omitempty"`
+	Poll       *Poll  `json:"poll,omitempty"`
+	// Attachments describes the uploaded files; it is derived from Files when empty
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Files are uploaded along with the message as multipart form data
+	Files []File `json:"-"`
+}
+
+// AllowedMentions controls which mentions in the content ping anyone. An empty
+// Parse list disables all mentions that aren't listed explicitly.
+type AllowedMentions struct {
+	Parse       []string `json:"parse"`
+	Roles       []string `json:"roles,omitempty"`
+	Users       []string `json:"users,omitempty"`
+	RepliedUser bool     `json:"replied_user,omitempty"`
+}
+
+// Embed represents a Discord embed
+type Embed struct {
+	Title       string          `json:"title,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Color       int             `json:"color,omitempty"`
+	Fields      []EmbedField    `json:"fields,omitempty"`
+	Author      *EmbedAuthor    `json:"author,omitempty"`
+	Footer      *EmbedFooter    `json:"footer,omitempty"`
+	Image       *EmbedImage     `json:"image,omitempty"`
+	Thumbnail   *EmbedThumbnail `json:"thumbnail,omitempty"`
+	Video       *EmbedVideo     `json:"video,omitempty"`
+	Provider    *EmbedProvider  `json:"provider,omitempty"`
+	Timestamp   string          `json:"timestamp,omitempty"`
+}
+
+// EmbedField represents a field in a Discord embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// EmbedAuthor represents the author of a Discord embed
+type EmbedAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+// EmbedFooter represents a footer in a Discord embed
+type EmbedFooter struct {
+	Text    string `json:"text"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+// EmbedImage represents an image in a Discord embed. The URL may reference an
+// uploaded file with AttachmentURL.
+type EmbedImage struct {
+	URL    string `json:"url"`
+	Height int    `json:"height,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+// EmbedThumbnail represents a thumbnail in a Discord embed. The URL may
+// reference an uploaded file with AttachmentURL.
+type EmbedThumbnail struct {
+	URL    string `json:"url"`
+	Height int    `json:"height,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+// EmbedVideo represents a video in a Discord embed
+type EmbedVideo struct {
+	URL    string `json:"url,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+// EmbedProvider represents the provider of a Discord embed
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Component represents a message component such as an action row or button.
+// Webhooks not owned by an application may only send link buttons.
+type Component struct {
+	Type       int         `json:"type"`
+	Components []Component `json:"components,omitempty"`
+	Style      int         `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	Emoji      *Emoji      `json:"emoji,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	URL        string      `json:"url,omitempty"`
+	Disabled   bool        `json:"disabled,omitempty"`
+}
+
+// Emoji represents a unicode or custom emoji
+type Emoji struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Animated bool   `json:"animated,omitempty"`
+}
+
+// Poll represents a poll attached to a message
+type Poll struct {
+	Question PollMedia    `json:"question"`
+	Answers  []PollAnswer `json:"answers"`
+	// Duration is the number of hours the poll is open for
+	Duration         int  `json:"duration,omitempty"`
+	AllowMultiselect bool `json:"allow_multiselect,omitempty"`
+	LayoutType       int  `json:"layout_type,omitempty"`
+}
+
+// PollMedia represents the text and emoji of a poll question or answer
+type PollMedia struct {
+	Text  string `json:"text,omitempty"`
+	Emoji *Emoji `json:"emoji,omitempty"`
+}
+
+// PollAnswer represents an answer of a poll
+type PollAnswer struct {
+	PollMedia PollMedia `json:"poll_media"`
+}
+
+// SentMessage represents a message created by Discord, as returned when the
+// webhook is executed with wait=true
+type SentMessage struct {
+	ID              string       `json:"id"`
+	ChannelID       string       `json:"channel_id"`
+	WebhookID       string       `json:"webhook_id,omitempty"`
+	Content         string       `json:"content"`
+	Timestamp       string       `json:"timestamp"`
+	EditedTimestamp string       `json:"edited_timestamp,omitempty"`
+	Embeds          []Embed      `json:"embeds"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
+	Flags           int          `json:"flags,omitempty"`
+}
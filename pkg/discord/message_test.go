@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessage_FullPayloadJSON(t *testing.T) {
+	msg := Message{
+		Username:  "Grafana",
+		AvatarURL: "https://example.com/avatar.png",
+		Content:   "Test content",
+		TTS:       true,
+		Flags:     MessageFlagSuppressNotifications,
+		AllowedMentions: &AllowedMentions{
+			Parse: []string{AllowedMentionUsers},
+			Roles: []string{"123"},
+		},
+		Embeds: []Embed{
+			{
+				Title:     "Title",
+				Author:    &EmbedAuthor{Name: "Grafana", URL: "https://grafana.com"},
+				Thumbnail: &EmbedThumbnail{URL: "https://example.com/thumb.png"},
+				Image:     &EmbedImage{URL: "https://example.com/image.png"},
+				Video:     &EmbedVideo{URL: "https://example.com/video.mp4"},
+				Provider:  &EmbedProvider{Name: "Grafana"},
+			},
+		},
+		Components: []Component{
+			{
+				Type: ComponentTypeActionRow,
+				Components: []Component{
+					{
+						Type:  ComponentTypeButton,
+						Style: ButtonStyleLink,
+						Label: "Dashboard",
+						URL:   "https://monitoring.example.com/d/abc",
+					},
+				},
+			},
+		},
+		Poll: &Poll{
+			Question: PollMedia{Text: "Acknowledge?"},
+			Answers: []PollAnswer{
+				{PollMedia: PollMedia{Text: "Yes", Emoji: &Emoji{Name: "✅"}}},
+				{PollMedia: PollMedia{Text: "No"}},
+			},
+			Duration: 1,
+		},
+	}
+
+	// Verify it can be marshaled
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+
+	// Verify it can be unmarshaled
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal message: %v", err)
+	}
+
+	// Verify key fields
+	if decoded.AvatarURL != msg.AvatarURL {
+		t.Errorf("AvatarURL = %q, want %q", decoded.AvatarURL, msg.AvatarURL)
+	}
+
+	if !decoded.TTS {
+		t.Error("TTS = false, want true")
+	}
+
+	if decoded.Flags != MessageFlagSuppressNotifications {
+		t.Errorf("Flags = %d, want %d", decoded.Flags, MessageFlagSuppressNotifications)
+	}
+
+	if decoded.AllowedMentions == nil || len(decoded.AllowedMentions.Roles) != 1 {
+		t.Fatalf("AllowedMentions = %+v, want one role", decoded.AllowedMentions)
+	}
+
+	embed := decoded.Embeds[0]
+	if embed.Author == nil || embed.Author.Name != "Grafana" {
+		t.Errorf("Embed.Author = %+v, want name %q", embed.Author, "Grafana")
+	}
+
+	if embed.Thumbnail == nil || embed.Image == nil || embed.Video == nil || embed.Provider == nil {
+		t.Errorf("Embed media = %+v, want thumbnail, image, video and provider", embed)
+	}
+
+	if len(decoded.Components) != 1 || len(decoded.Components[0].Components) != 1 {
+		t.Fatalf("Components = %+v, want one action row with one button", decoded.Components)
+	}
+
+	if button := decoded.Components[0].Components[0]; button.Style != ButtonStyleLink || button.URL == "" {
+		t.Errorf("button = %+v, want link button", button)
+	}
+
+	if decoded.Poll == nil || len(decoded.Poll.Answers) != 2 {
+		t.Fatalf("Poll = %+v, want two answers", decoded.Poll)
+	}
+}
+
+func TestMessage_OmitEmpty(t *testing.T) {
+	data, err := json.Marshal(Message{Content: "Only content"})
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal to map: %v", err)
+	}
+
+	for _, key := range []string{"avatar_url", "tts", "allowed_mentions", "flags", "components", "attachments", "poll", "embeds"} {
+		if _, exists := decoded[key]; exists {
+			t.Errorf("Expected %s to be omitted, but it exists", key)
+		}
+	}
+}
+
+func TestAllowedMentions_EmptyParse(t *testing.T) {
+	// An empty parse list must be sent to disable mentions
+	data, err := json.Marshal(AllowedMentions{Parse: []string{}})
+	if err != nil {
+		t.Fatalf("Failed to marshal allowed mentions: %v", err)
+	}
+
+	if string(data) != `{"parse":[]}` {
+		t.Errorf("allowed mentions = %s, want %s", data, `{"parse":[]}`)
+	}
+}
@@ -0,0 +1,185 @@
+package discord
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often buckets whose reset has passed are dropped
+const pruneInterval = time.Minute
+
+// rateLimiter tracks Discord rate limit buckets so requests wait for a free
+// slot instead of being rejected with 429
+type rateLimiter struct {
+	mu      sync.Mutex
+	routes  map[string]string  // route -> bucket id reported by Discord
+	buckets map[string]*bucket // bucket id (or route until known) -> state
+	// globalResetAt blocks every route after a global 429 until it passes
+	globalResetAt time.Time
+	lastPrune     time.Time
+}
+
+// bucket holds the last known state of a rate limit bucket
+type bucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		routes:  make(map[string]string),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// bucketFor returns the bucket used by a route, creating an unknown one if needed
+func (l *rateLimiter) bucketFor(route string) *bucket {
+	key := route
+	if id, ok := l.routes[route]; ok {
+		key = id
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{remaining: -1}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// reserve claims a request slot for the route. It returns how long the caller
+// must wait before trying again, or zero if the request may be sent now.
+func (l *rateLimiter) reserve(route string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastPrune) >= pruneInterval {
+		l.prune(now)
+	}
+	if now.Before(l.globalResetAt) {
+		return l.globalResetAt.Sub(now)
+	}
+
+	b := l.bucketFor(route)
+	if !b.resetAt.IsZero() && !now.Before(b.resetAt) {
+		// The bucket has been reset; allow requests until Discord tells us otherwise
+		b.remaining = -1
+		b.resetAt = time.Time{}
+	}
+	if b.remaining == 0 {
+		return b.resetAt.Sub(now)
+	}
+	if b.remaining > 0 {
+		b.remaining--
+	}
+	return 0
+}
+
+// prune drops the buckets whose reset has passed, or that were never
+// reported by Discord, along with the routes pointing at them. Edit and delete
+// routes name a message, so without this the maps would grow forever.
+// The caller must hold l.mu.
+func (l *rateLimiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.resetAt) {
+			delete(l.buckets, key)
+		}
+	}
+	for route, id := range l.routes {
+		if _, ok := l.buckets[id]; !ok {
+			delete(l.routes, route)
+		}
+	}
+	l.lastPrune = now
+}
+
+// wait blocks until the route has a free request slot or ctx is done
+func (l *rateLimiter) wait(ctx context.Context, route string) error {
+	for {
+		delay := l.reserve(route, time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// update records the rate limit headers returned by Discord for a route
+func (l *rateLimiter) update(route string, header http.Header, now time.Time) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAfter, ok := parseSeconds(header.Get("X-RateLimit-Reset-After"))
+	if !ok {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if id := header.Get("X-RateLimit-Bucket"); id != "" {
+		if prev, ok := l.routes[route]; !ok || prev != id {
+			l.routes[route] = id
+			delete(l.buckets, route)
+		}
+	}
+
+	b := l.bucketFor(route)
+	b.remaining = remaining
+	b.resetAt = now.Add(resetAfter)
+}
+
+// limit marks the route, or every route for a global limit, as exhausted for
+// the given duration, used after a 429
+func (l *rateLimiter) limit(route string, retryAfter time.Duration, global bool, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if global {
+		if resetAt := now.Add(retryAfter); resetAt.After(l.globalResetAt) {
+			l.globalResetAt = resetAt
+		}
+		return
+	}
+
+	b := l.bucketFor(route)
+	b.remaining = 0
+	if resetAt := now.Add(retryAfter); resetAt.After(b.resetAt) {
+		b.resetAt = resetAt
+	}
+}
+
+// retryAfter returns how long Discord asked us to wait after a 429 response
+func retryAfter(header http.Header) time.Duration {
+	if d, ok := parseSeconds(header.Get("Retry-After")); ok {
+		return d
+	}
+	if d, ok := parseSeconds(header.Get("X-RateLimit-Reset-After")); ok {
+		return d
+	}
+	return time.Second
+}
+
+// isGlobal reports whether a 429 response hit the global rate limit rather
+// than the limit of its route
+func isGlobal(header http.Header) bool {
+	return header.Get("X-RateLimit-Global") == "true" || header.Get("X-RateLimit-Scope") == "global"
+}
+
+// parseSeconds parses a (possibly fractional) number of seconds as sent by Discord
+func parseSeconds(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil || secs < 0 || math.IsInf(secs, 0) || math.IsNaN(secs) {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
@@ -0,0 +1,141 @@
+package discord
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC)
+
+	// Unknown buckets never block
+	if delay := limiter.reserve("route", now); delay != 0 {
+		t.Errorf("reserve() on unknown bucket = %v, want 0", delay)
+	}
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "1")
+	header.Set("X-RateLimit-Reset-After", "2.5")
+	header.Set("X-RateLimit-Bucket", "abc")
+	limiter.update("route", header, now)
+
+	if delay := limiter.reserve("route", now); delay != 0 {
+		t.Errorf("reserve() with remaining requests = %v, want 0", delay)
+	}
+	if delay := limiter.reserve("route", now); delay != 2500*time.Millisecond {
+		t.Errorf("reserve() on exhausted bucket = %v, want %v", delay, 2500*time.Millisecond)
+	}
+	if delay := limiter.reserve("route", now.Add(3*time.Second)); delay != 0 {
+		t.Errorf("reserve() after reset = %v, want 0", delay)
+	}
+}
+
+func TestRateLimiter_SharedBucket(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset-After", "1")
+	header.Set("X-RateLimit-Bucket", "shared")
+	limiter.update("first", header, now)
+	limiter.update("second", header, now)
+
+	if delay := limiter.reserve("second", now); delay != time.Second {
+		t.Errorf("reserve() on shared bucket = %v, want %v", delay, time.Second)
+	}
+}
+
+func TestRateLimiter_Global(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("Retry-After", "2")
+	header.Set("X-RateLimit-Scope", "global")
+	limiter.limit("first", retryAfter(header), isGlobal(header), now)
+
+	if delay := limiter.reserve("second", now); delay != 2*time.Second {
+		t.Errorf("reserve() on another route = %v, want %v", delay, 2*time.Second)
+	}
+	if delay := limiter.reserve("second", now.Add(2*time.Second)); delay != 0 {
+		t.Errorf("reserve() after the global reset = %v, want 0", delay)
+	}
+
+	header = http.Header{}
+	header.Set("X-RateLimit-Global", "true")
+	if !isGlobal(header) {
+		t.Error("isGlobal() = false, want true for X-RateLimit-Global")
+	}
+	if isGlobal(http.Header{"X-Ratelimit-Scope": {"user"}}) {
+		t.Error("isGlobal() = true, want false for a per-route limit")
+	}
+}
+
+func TestRateLimiter_Prune(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset-After", "1")
+	for _, id := range []string{"1", "2", "3"} {
+		header.Set("X-RateLimit-Bucket", "bucket-"+id)
+		limiter.update("PATCH /messages/"+id, header, now)
+	}
+
+	header.Set("X-RateLimit-Reset-After", "120")
+	header.Set("X-RateLimit-Bucket", "long")
+	limiter.update("POST /webhook", header, now)
+
+	limiter.reserve("POST /webhook", now.Add(pruneInterval))
+	if len(limiter.routes) != 1 || len(limiter.buckets) != 1 {
+		t.Errorf("routes/buckets = %v/%d, want only the bucket that has not reset", limiter.routes, len(limiter.buckets))
+	}
+	if delay := limiter.reserve("POST /webhook", now.Add(pruneInterval)); delay != time.Minute {
+		t.Errorf("reserve() on a kept bucket = %v, want %v", delay, time.Minute)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+	}{
+		{
+			name:   "retry after header",
+			header: map[string]string{"Retry-After": "3"},
+			want:   3 * time.Second,
+		},
+		{
+			name:   "fractional seconds",
+			header: map[string]string{"Retry-After": "0.25"},
+			want:   250 * time.Millisecond,
+		},
+		{
+			name:   "falls back to reset after",
+			header: map[string]string{"X-RateLimit-Reset-After": "1.5"},
+			want:   1500 * time.Millisecond,
+		},
+		{
+			name:   "default when missing",
+			header: map[string]string{},
+			want:   time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			if got := retryAfter(header); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
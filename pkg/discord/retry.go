@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls how transient Discord failures are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry; it doubles on every retry
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// Jitter randomizes each delay by up to this fraction (0 to 1)
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that are retried
+	RetryableStatusCodes []int
+	// RetryableError reports whether a transport error is retried. All
+	// transport errors are retried when nil.
+	RetryableError func(error) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewWebhook
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// shouldRetryStatus reports whether a response status is retryable
+func (p RetryPolicy) shouldRetryStatus(status int) bool {
+	return slices.Contains(p.RetryableStatusCodes, status)
+}
+
+// shouldRetryError reports whether a transport error is retryable
+func (p RetryPolicy) shouldRetryError(err error) bool {
+	if p.RetryableError == nil {
+		return true
+	}
+	return p.RetryableError(err)
+}
+
+// canRetry reports whether another attempt is allowed after the given one (1-based)
+func (p RetryPolicy) canRetry(attempt int) bool {
+	return attempt < p.MaxAttempts
+}
+
+// backoff returns the delay before the retry following the given attempt (1-based)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		// Spread delays in [delay*(1-jitter), delay*(1+jitter)]
+		jitter := min(p.Jitter, 1)
+		spread := float64(delay) * jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+
+	return delay
+}
@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 3, want: 400 * time.Millisecond},
+		{attempt: 4, want: 800 * time.Millisecond},
+		{attempt: 5, want: time.Second},
+		{attempt: 50, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+
+	// A zero MaxBackoff means no cap: delays keep doubling
+	uncapped := RetryPolicy{BaseBackoff: 100 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: 1600 * time.Millisecond,
+	} {
+		if got := uncapped.backoff(attempt); got != want {
+			t.Errorf("uncapped backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	if got := uncapped.backoff(100); got <= 0 {
+		t.Errorf("uncapped backoff(100) = %v, want a positive delay", got)
+	}
+}
+
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+		Jitter:      0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(1)
+		if got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want within [50ms, 150ms]", got)
+		}
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	if !policy.shouldRetryStatus(http.StatusBadGateway) {
+		t.Error("shouldRetryStatus(502) = false, want true")
+	}
+	if policy.shouldRetryStatus(http.StatusBadRequest) {
+		t.Error("shouldRetryStatus(400) = true, want false")
+	}
+	if !policy.shouldRetryError(errors.New("connection reset")) {
+		t.Error("shouldRetryError() = false, want true with nil RetryableError")
+	}
+
+	policy.RetryableError = func(error) bool { return false }
+	if policy.shouldRetryError(errors.New("connection reset")) {
+		t.Error("shouldRetryError() = true, want false with custom RetryableError")
+	}
+
+	if !policy.canRetry(2) || policy.canRetry(3) {
+		t.Error("canRetry() should allow attempts below MaxAttempts only")
+	}
+}
@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// partMarkerLength is the room kept in the content of each part for its
+// "(1/3)" marker
+const partMarkerLength = 16
+
+// Split breaks the message into an ordered sequence of messages that each fit
+// Discord's limits. Field values that are too long are continued in further
+// fields, embeds with too many fields are continued in further embeds, and
+// embeds and content are spread over as many messages as needed. Any other
+// text over its limit, such as an embed title or description, is truncated.
+// Every part keeps the username, avatar, mention and flag settings; files,
+// polls, components and the thread name stay on the first part only. When the
+// message is split, each part's content is prefixed with its position, e.g.
+// "(1/3)". A message that already fits is returned unchanged.
+func (m Message) Split() []Message {
+	if m.Validate() == nil {
+		return []Message{m}
+	}
+
+	var embeds []Embed
+	for _, embed := range m.Embeds {
+		embeds = append(embeds, embed.split()...)
+	}
+
+	// Pack embeds into groups that fit a single message
+	var groups [][]Embed
+	var group []Embed
+	groupLength := 0
+	for _, embed := range embeds {
+		length := embed.length()
+		if len(group) > 0 && (len(group) == MaxEmbeds || groupLength+length > MaxEmbedsTotalLength) {
+			groups = append(groups, group)
+			group = nil
+			groupLength = 0
+		}
+		group = append(group, embed)
+		groupLength += length
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	contents := splitText(m.Content, MaxContentLength-partMarkerLength)
+	total := max(len(groups), len(contents), 1)
+
+	parts := make([]Message, total)
+	for i := range parts {
+		part := m
+		part.Content = ""
+		part.Embeds = nil
+		if i < len(contents) {
+			part.Content = contents[i]
+		}
+		if i < len(groups) {
+			part.Embeds = groups[i]
+		}
+		if i > 0 {
+			part.ThreadName = ""
+			part.Poll = nil
+			part.Components = nil
+			part.Attachments = nil
+			part.Files = nil
+		}
+		if total > 1 {
+			part.Content = strings.TrimSpace(fmt.Sprintf("(%d/%d) %s", i+1, total, part.Content))
+		}
+		parts[i] = part.Truncate()
+	}
+
+	return parts
+}
+
+// SendSplit splits the message with Split and sends every part in order. When
+// the first part creates a forum post, the remaining parts are sent into it.
+func (w *Webhook) SendSplit(ctx context.Context, msg Message) error {
+	parts := msg.Split()
+	if len(parts) == 1 {
+		return w.SendContext(ctx, parts[0])
+	}
+
+	target := w
+	for i, part := range parts {
+		if i == 0 && part.ThreadName != "" {
+			sent, err := target.SendWait(ctx, part)
+			if err != nil {
+				return fmt.Errorf("part %d/%d: %w", i+1, len(parts), err)
+			}
+			// The channel of a new forum post is its thread
+			target = w.Thread(sent.ChannelID)
+			continue
+		}
+		if err := target.SendContext(ctx, part); err != nil {
+			return fmt.Errorf("part %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+
+	return nil
+}
+
+// split breaks the embed into embeds that each fit Discord's limits. The
+// first embed keeps all details; continuations repeat the title and color.
+func (e Embed) split() []Embed {
+	e.Fields = splitFields(e.Fields)
+	e = e.truncateText()
+	if len(e.Fields) <= MaxEmbedFields && e.length() <= MaxEmbedsTotalLength {
+		return []Embed{e}
+	}
+
+	var embeds []Embed
+	current := e
+	current.Fields = nil
+	for _, field := range e.Fields {
+		length := runeCount(field.Name) + runeCount(field.Value)
+		if len(current.Fields) > 0 && (len(current.Fields) == MaxEmbedFields || current.length()+length > MaxEmbedsTotalLength) {
+			embeds = append(embeds, current)
+			current = Embed{Title: e.Title, Type: e.Type, URL: e.URL, Color: e.Color}
+		}
+		current.Fields = append(current.Fields, field)
+	}
+	return append(embeds, current)
+}
+
+// splitFields continues field values that are too long in further fields
+// with a blank name
+func splitFields(fields []EmbedField) []EmbedField {
+	var split []EmbedField
+	for _, field := range fields {
+		if runeCount(field.Value) <= MaxFieldValueLength {
+			split = append(split, field)
+			continue
+		}
+		for i, value := range splitText(field.Value, MaxFieldValueLength) {
+			if i > 0 {
+				field.Name = "\u200b"
+			}
+			field.Value = value
+			split = append(split, field)
+		}
+	}
+	return split
+}
+
+// splitText breaks s into chunks of at most size runes, preferring to break
+// after a newline
+func splitText(s string, size int) []string {
+	var chunks []string
+	runes := []rune(s)
+	for len(runes) > size {
+		cut := size
+		for i := size - 1; i > size/2; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	if len(runes) > 0 {
+		chunks = append(chunks, string(runes))
+	}
+	return chunks
+}
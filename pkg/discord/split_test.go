@@ -0,0 +1,200 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMessage_Split_FitsUnchanged(t *testing.T) {
+	msg := Message{Username: "Grafana", Content: "Test", Embeds: []Embed{{Title: "Title"}}}
+
+	parts := msg.Split()
+	if len(parts) != 1 {
+		t.Fatalf("Split() = %d parts, want 1", len(parts))
+	}
+	if parts[0].Content != "Test" {
+		t.Errorf("content = %q, want %q", parts[0].Content, "Test")
+	}
+}
+
+func TestMessage_Split_TooManyEmbeds(t *testing.T) {
+	msg := Message{
+		Username:  "Grafana",
+		AvatarURL: "https://example.com/avatar.png",
+		Embeds:    make([]Embed, 23),
+	}
+	for i := range msg.Embeds {
+		msg.Embeds[i].Title = fmt.Sprintf("Alert %d", i)
+	}
+
+	parts := msg.Split()
+	if len(parts) != 3 {
+		t.Fatalf("Split() = %d parts, want 3", len(parts))
+	}
+
+	for i, part := range parts {
+		if err := part.Validate(); err != nil {
+			t.Errorf("part %d is invalid: %v", i, err)
+		}
+		if part.Username != "Grafana" || part.AvatarURL != msg.AvatarURL {
+			t.Errorf("part %d username/avatar = %q/%q, want carried over", i, part.Username, part.AvatarURL)
+		}
+		if want := fmt.Sprintf("(%d/3)", i+1); part.Content != want {
+			t.Errorf("part %d content = %q, want %q", i, part.Content, want)
+		}
+	}
+
+	// Order is preserved
+	if parts[1].Embeds[0].Title != "Alert 10" || parts[2].Embeds[2].Title != "Alert 22" {
+		t.Error("Split() did not preserve embed order")
+	}
+}
+
+func TestMessage_Split_TotalLength(t *testing.T) {
+	msg := Message{
+		Embeds: []Embed{
+			{Description: strings.Repeat("a", 4000)},
+			{Description: strings.Repeat("b", 4000)},
+			{Description: strings.Repeat("c", 100)},
+		},
+	}
+
+	parts := msg.Split()
+	if len(parts) != 2 {
+		t.Fatalf("Split() = %d parts, want 2", len(parts))
+	}
+	if len(parts[0].Embeds) != 1 || len(parts[1].Embeds) != 2 {
+		t.Errorf("embeds per part = %d/%d, want 1/2", len(parts[0].Embeds), len(parts[1].Embeds))
+	}
+}
+
+func TestMessage_Split_TooManyFields(t *testing.T) {
+	embed := Embed{Title: "Alerts", Color: 15158332, Footer: &EmbedFooter{Text: "Footer"}}
+	for i := 0; i < 30; i++ {
+		embed.Fields = append(embed.Fields, EmbedField{Name: fmt.Sprintf("Field %d", i), Value: "Value"})
+	}
+
+	parts := Message{Embeds: []Embed{embed}}.Split()
+	if len(parts) != 1 {
+		t.Fatalf("Split() = %d parts, want 1", len(parts))
+	}
+
+	embeds := parts[0].Embeds
+	if len(embeds) != 2 {
+		t.Fatalf("embeds = %d, want 2", len(embeds))
+	}
+	if len(embeds[0].Fields) != MaxEmbedFields || len(embeds[1].Fields) != 5 {
+		t.Errorf("fields per embed = %d/%d, want %d/5", len(embeds[0].Fields), len(embeds[1].Fields), MaxEmbedFields)
+	}
+	if embeds[1].Title != "Alerts" || embeds[1].Color != 15158332 {
+		t.Errorf("continuation embed = %+v, want title and color carried over", embeds[1])
+	}
+}
+
+func TestMessage_Split_LongFieldValue(t *testing.T) {
+	value := strings.Repeat("line\n", 300)
+	embed := Embed{Title: "Alert", Fields: []EmbedField{{Name: "Details", Value: value}, {Name: "Next", Value: "Value"}}}
+
+	parts := Message{Embeds: []Embed{embed}}.Split()
+	if len(parts) != 1 || len(parts[0].Embeds) != 1 {
+		t.Fatalf("Split() = %+v, want a single embed", parts)
+	}
+
+	fields := parts[0].Embeds[0].Fields
+	if len(fields) != 3 {
+		t.Fatalf("fields = %d, want 3", len(fields))
+	}
+	if fields[0].Name != "Details" || fields[1].Name != "\u200b" || fields[2].Name != "Next" {
+		t.Errorf("field names = %q/%q/%q, want the value continued under a blank name", fields[0].Name, fields[1].Name, fields[2].Name)
+	}
+	if got := fields[0].Value + fields[1].Value; got != value {
+		t.Errorf("field values = %q, want the whole value %q", got, value)
+	}
+}
+
+func TestMessage_Split_LongContent(t *testing.T) {
+	msg := Message{
+		Content:    strings.Repeat("line\n", 1000),
+		ThreadName: "HighCPU",
+		Files:      []File{{Name: "payload.json", Data: []byte("{}")}},
+	}
+
+	parts := msg.Split()
+	if len(parts) != 3 {
+		t.Fatalf("Split() = %d parts, want 3", len(parts))
+	}
+	for i, part := range parts {
+		if err := part.Validate(); err != nil {
+			t.Errorf("part %d is invalid: %v", i, err)
+		}
+	}
+
+	if parts[0].ThreadName != "HighCPU" || len(parts[0].Files) != 1 {
+		t.Error("first part should keep the thread name and files")
+	}
+	if parts[1].ThreadName != "" || len(parts[1].Files) != 0 {
+		t.Error("later parts should not repeat the thread name or files")
+	}
+}
+
+func TestWebhook_SendSplit_ForumPost(t *testing.T) {
+	var received []Message
+	var threadIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		received = append(received, msg)
+		threadIDs = append(threadIDs, r.URL.Query().Get("thread_id"))
+
+		if r.URL.Query().Get("wait") == "true" {
+			_, _ = w.Write([]byte(`{"id": "1001", "channel_id": "3003"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := Message{ThreadName: "HighCPU", Embeds: make([]Embed, 15)}
+	for i := range msg.Embeds {
+		msg.Embeds[i].Title = fmt.Sprintf("Alert %d", i)
+	}
+
+	webhook := NewWebhook(server.URL)
+	if err := webhook.SendSplit(context.Background(), msg); err != nil {
+		t.Fatalf("SendSplit() error = %v, want nil", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("requests = %d, want 2", len(received))
+	}
+	if received[0].ThreadName != "HighCPU" || threadIDs[0] != "" {
+		t.Errorf("first part thread_name/thread_id = %q/%q, want new forum post", received[0].ThreadName, threadIDs[0])
+	}
+	if threadIDs[1] != "3003" {
+		t.Errorf("second part thread_id = %q, want %q", threadIDs[1], "3003")
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	chunks := splitText("aaaa\nbbbb\ncccc", 7)
+	want := []string{"aaaa\n", "bbbb\n", "cccc"}
+	if len(chunks) != len(want) {
+		t.Fatalf("splitText() = %q, want %q", chunks, want)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+
+	if chunks := splitText("", 10); len(chunks) != 0 {
+		t.Errorf("splitText(\"\") = %q, want no chunks", chunks)
+	}
+}
@@ -1,70 +1,235 @@
 package discord
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
-// Webhook represents a Discord webhook client
+// Webhook represents a Discord webhook client. A Webhook with only its URL set
+// sends with the default settings, sharing one client with every other such
+// Webhook; use NewWebhook to configure it.
 type Webhook struct {
 	URL string
-}
+	*client
 
-// Message represents a Discord message payload
-type Message struct {
-	Username string  `json:"username,omitempty"`
-	Content  string  `json:"content,omitempty"`
-	Embeds   []Embed `json:"embeds,omitempty"`
+	target   string
+	threadID string
 }
 
-// Embed represents a Discord embed
-type Embed struct {
-	Title       string       `json:"title,omitempty"`
-	Type        string       `json:"type,omitempty"`
-	URL         string       `json:"url,omitempty"`
-	Description string       `json:"description,omitempty"`
-	Color       int          `json:"color,omitempty"`
-	Fields      []EmbedField `json:"fields,omitempty"`
-	Footer      *EmbedFooter `json:"footer,omitempty"`
-	Timestamp   string       `json:"timestamp,omitempty"`
-}
+// NewWebhook creates a new Discord webhook client
+func NewWebhook(url string, opts ...Option) *Webhook {
+	w := &Webhook{URL: url, client: newClient(opts), target: url}
 
-// EmbedField represents a field in a Discord embed
-type EmbedField struct {
-	Name   string `json:"name"`
-	Value  string `json:"value"`
-	Inline bool   `json:"inline,omitempty"`
-}
+	// Send official webhook URLs to an overridden API base instead
+	if w.apiBase != DefaultAPIBase {
+		if parsed, err := ParseWebhookURL(url, ""); err == nil {
+			w.target = w.apiBase + "/webhooks/" + parsed.ID + "/" + parsed.Token
+			if parsed.url.RawQuery != "" {
+				w.target += "?" + parsed.url.RawQuery
+			}
+		}
+	}
 
-// EmbedFooter represents a footer in a Discord embed
-type EmbedFooter struct {
-	Text    string `json:"text"`
-	IconURL string `json:"icon_url,omitempty"`
+	return w
 }
 
-// NewWebhook creates a new Discord webhook client
-func NewWebhook(url string) *Webhook {
-	return &Webhook{URL: url}
+// Thread returns a copy of the webhook whose requests target the given thread.
+// The copy shares the HTTP client and rate limit state with w.
+func (w *Webhook) Thread(threadID string) *Webhook {
+	thread := *w
+	thread.threadID = threadID
+	return &thread
 }
 
 // Send sends a message to the Discord webhook
 func (w *Webhook) Send(msg Message) error {
-	payload, err := json.Marshal(msg)
+	return w.SendContext(context.Background(), msg)
+}
+
+// SendContext sends a message to the Discord webhook, giving up when ctx is done
+func (w *Webhook) SendContext(ctx context.Context, msg Message) error {
+	payload, contentType, err := encodeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := w.endpoint("", nil)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return err
 	}
 
-	resp, err := http.Post(w.URL, "application/json", bytes.NewBuffer(payload))
+	resp, body, err := w.do(ctx, http.MethodPost, endpoint, payload, contentType)
 	if err != nil {
 		return fmt.Errorf("failed to send webhook: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newAPIError(resp, body)
 	}
 
 	return nil
 }
+
+// SendWait sends a message to the Discord webhook and returns the message
+// created by Discord
+func (w *Webhook) SendWait(ctx context.Context, msg Message) (*SentMessage, error) {
+	payload, contentType, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := w.endpoint("", url.Values{"wait": {"true"}})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := w.do(ctx, http.MethodPost, endpoint, payload, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, body)
+	}
+
+	var sent SentMessage
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	return &sent, nil
+}
+
+// EditMessage edits a message previously sent by the webhook
+func (w *Webhook) EditMessage(id string, msg Message) (*SentMessage, error) {
+	return w.EditMessageContext(context.Background(), id, msg)
+}
+
+// EditMessageContext edits a message previously sent by the webhook, giving up
+// when ctx is done
+func (w *Webhook) EditMessageContext(ctx context.Context, id string, msg Message) (*SentMessage, error) {
+	payload, contentType, err := encodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := w.endpoint("/messages/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, body, err := w.do(ctx, http.MethodPatch, endpoint, payload, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, body)
+	}
+
+	var edited SentMessage
+	if err := json.Unmarshal(body, &edited); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	return &edited, nil
+}
+
+// DeleteMessage deletes a message previously sent by the webhook
+func (w *Webhook) DeleteMessage(id string) error {
+	return w.DeleteMessageContext(context.Background(), id)
+}
+
+// DeleteMessageContext deletes a message previously sent by the webhook, giving
+// up when ctx is done
+func (w *Webhook) DeleteMessageContext(ctx context.Context, id string) error {
+	endpoint, err := w.endpoint("/messages/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, body, err := w.do(ctx, http.MethodDelete, endpoint, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp, body)
+	}
+
+	return nil
+}
+
+// WebhookInfo describes a webhook as returned by Discord
+type WebhookInfo struct {
+	ID            string `json:"id"`
+	Type          int    `json:"type"`
+	Name          string `json:"name"`
+	GuildID       string `json:"guild_id"`
+	ChannelID     string `json:"channel_id"`
+	ApplicationID string `json:"application_id,omitempty"`
+}
+
+// Info fetches the webhook from Discord, verifying that it exists
+func (w *Webhook) Info(ctx context.Context) (*WebhookInfo, error) {
+	resp, body, err := w.do(ctx, http.MethodGet, w.targetURL(), nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, body)
+	}
+
+	var info WebhookInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	return &info, nil
+}
+
+// endpoint builds a URL below the webhook URL with extra query parameters,
+// targeting the webhook's thread if one is set
+func (w *Webhook) endpoint(path string, query url.Values) (string, error) {
+	u, err := url.Parse(w.targetURL())
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", errors.Unwrap(err))
+	}
+	if path != "" {
+		u.Path = strings.TrimSuffix(u.Path, "/") + path
+	}
+	q := u.Query()
+	for key, values := range query {
+		q[key] = values
+	}
+	if w.threadID != "" {
+		q.Set("thread_id", w.threadID)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// targetURL returns the URL requests are sent to, falling back to URL for a
+// Webhook built without NewWebhook
+func (w *Webhook) targetURL() string {
+	if w.target == "" {
+		return w.URL
+	}
+	return w.target
+}
+
+// do performs a request with the webhook's client, or with the shared default
+// client for a Webhook built without NewWebhook
+func (w *Webhook) do(ctx context.Context, method, endpoint string, body []byte, contentType string) (*http.Response, []byte, error) {
+	c := w.client
+	if c == nil {
+		c = defaultClient()
+	}
+	return c.do(ctx, method, endpoint, body, contentType)
+}
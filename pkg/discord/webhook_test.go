@@ -1,10 +1,13 @@
 package discord
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewWebhook(t *testing.T) {
@@ -16,6 +19,25 @@ func TestNewWebhook(t *testing.T) {
 	}
 }
 
+func TestNewWebhook_Options(t *testing.T) {
+	client := &http.Client{}
+	webhook := NewWebhook("https://discord.com/api/webhooks/123/abc",
+		WithHTTPClient(client),
+		WithTimeout(time.Second),
+		WithUserAgent("test-agent"),
+	)
+
+	if webhook.httpClient != client {
+		t.Error("WithHTTPClient() did not set the client")
+	}
+	if webhook.timeout != time.Second {
+		t.Errorf("timeout = %v, want %v", webhook.timeout, time.Second)
+	}
+	if webhook.userAgent != "test-agent" {
+		t.Errorf("userAgent = %q, want %q", webhook.userAgent, "test-agent")
+	}
+}
+
 func TestWebhook_Send_Success(t *testing.T) {
 	// Create a test server that simulates Discord webhook endpoint
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -58,6 +80,21 @@ func TestWebhook_Send_Success(t *testing.T) {
 	}
 }
 
+func TestWebhook_Send_ZeroValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("thread_id") != "42" {
+			t.Errorf("thread_id = %q, want 42", r.URL.Query().Get("thread_id"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL}
+	if err := webhook.Thread("42").Send(Message{Content: "Test message"}); err != nil {
+		t.Errorf("Send() error = %v, want nil", err)
+	}
+}
+
 func TestWebhook_Send_InvalidStatusCode(t *testing.T) {
 	// Create a test server that returns a non-204 status code
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -163,6 +200,22 @@ func TestMessage_JSON(t *testing.T) {
 	}
 }
 
+func TestMessage_ThreadName(t *testing.T) {
+	data, err := json.Marshal(Message{Content: "Test", ThreadName: "HighCPU"})
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal to map: %v", err)
+	}
+
+	if val, exists := decoded["thread_name"]; !exists || val != "HighCPU" {
+		t.Errorf("thread_name = %v, want %q", val, "HighCPU")
+	}
+}
+
 func TestEmbed_OmitEmpty(t *testing.T) {
 	// Test that omitempty works correctly
 	embed := Embed{
@@ -214,3 +267,321 @@ func TestEmbedField_Inline(t *testing.T) {
 		t.Error("Expected inline to be true")
 	}
 }
+
+func TestWebhook_Send_RateLimited(t *testing.T) {
+	// Reject the first request with 429, then accept
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0.05")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset-After", "0.05")
+			w.Header().Set("X-RateLimit-Bucket", "abc")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	start := time.Now()
+	if err := webhook.Send(Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Send() returned after %v, want it to wait for Retry-After", elapsed)
+	}
+}
+
+func TestWebhook_Send_WaitsForExhaustedBucket(t *testing.T) {
+	// Report an exhausted bucket on a successful request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.05")
+		w.Header().Set("X-RateLimit-Bucket", "abc")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	if err := webhook.Send(Message{Content: "First"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	start := time.Now()
+	if err := webhook.Send(Message{Content: "Second"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Send() returned after %v, want it to wait for the bucket reset", elapsed)
+	}
+}
+
+func TestWebhook_Send_RetriesServerErrors(t *testing.T) {
+	// Fail twice with a retryable status, then accept
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	webhook.Retry.BaseBackoff = time.Millisecond
+
+	if err := webhook.Send(Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestWebhook_Send_RetryExhausted(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	webhook.Retry.MaxAttempts = 2
+	webhook.Retry.BaseBackoff = time.Millisecond
+
+	if err := webhook.Send(Message{Content: "Test"}); err == nil {
+		t.Fatal("Send() error = nil, want error")
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestWebhook_SendContext_UserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("Expected User-Agent test-agent, got %s", ua)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, WithUserAgent("test-agent"))
+	if err := webhook.SendContext(context.Background(), Message{Content: "Test"}); err != nil {
+		t.Errorf("SendContext() error = %v, want nil", err)
+	}
+}
+
+func TestWebhook_SendContext_Timeout(t *testing.T) {
+	// Simulate a hung Discord connection
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	webhook := NewWebhook(server.URL, WithTimeout(20*time.Millisecond))
+	webhook.Retry.MaxAttempts = 1
+
+	if err := webhook.SendContext(context.Background(), Message{Content: "Test"}); err == nil {
+		t.Error("SendContext() error = nil, want timeout error")
+	}
+}
+
+func TestWebhook_SendContext_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	webhook.Retry.BaseBackoff = time.Minute
+
+	// The context ends while waiting for the first retry
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := webhook.SendContext(ctx, Message{Content: "Test"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SendContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWebhook_SendWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait := r.URL.Query().Get("wait"); wait != "true" {
+			t.Errorf("Expected wait=true, got %q", wait)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "1001",
+			"channel_id": "2002",
+			"webhook_id": "123",
+			"content": "Test",
+			"timestamp": "2026-02-02T12:00:00.000000+00:00",
+			"embeds": [{"title": "Test Embed", "color": 15158332}]
+		}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	sent, err := webhook.SendWait(context.Background(), Message{Content: "Test"})
+	if err != nil {
+		t.Fatalf("SendWait() error = %v, want nil", err)
+	}
+
+	if sent.ID != "1001" {
+		t.Errorf("ID = %q, want %q", sent.ID, "1001")
+	}
+	if sent.ChannelID != "2002" {
+		t.Errorf("ChannelID = %q, want %q", sent.ChannelID, "2002")
+	}
+	if sent.Timestamp != "2026-02-02T12:00:00.000000+00:00" {
+		t.Errorf("Timestamp = %q, want %q", sent.Timestamp, "2026-02-02T12:00:00.000000+00:00")
+	}
+	if len(sent.Embeds) != 1 || sent.Embeds[0].Title != "Test Embed" {
+		t.Errorf("Embeds = %+v, want one embed titled %q", sent.Embeds, "Test Embed")
+	}
+}
+
+func TestWebhook_SendWait_KeepsQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if threadID := r.URL.Query().Get("thread_id"); threadID != "42" {
+			t.Errorf("Expected thread_id=42, got %q", threadID)
+		}
+		_, _ = w.Write([]byte(`{"id": "1001"}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "?thread_id=42")
+	if _, err := webhook.SendWait(context.Background(), Message{Content: "Test"}); err != nil {
+		t.Errorf("SendWait() error = %v, want nil", err)
+	}
+}
+
+func TestWebhook_SendWait_UnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL)
+	if _, err := webhook.SendWait(context.Background(), Message{Content: "Test"}); err == nil {
+		t.Error("SendWait() error = nil, want error")
+	}
+}
+
+func TestWebhook_EditMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH request, got %s", r.Method)
+		}
+		if r.URL.Path != "/webhooks/123/abc/messages/1001" {
+			t.Errorf("Expected path /webhooks/123/abc/messages/1001, got %s", r.URL.Path)
+		}
+
+		var msg Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		_, _ = w.Write([]byte(`{"id": "1001", "channel_id": "2002", "content": "` + msg.Content + `"}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "/webhooks/123/abc")
+	edited, err := webhook.EditMessage("1001", Message{Content: "Resolved"})
+	if err != nil {
+		t.Fatalf("EditMessage() error = %v, want nil", err)
+	}
+
+	if edited.ID != "1001" {
+		t.Errorf("ID = %q, want %q", edited.ID, "1001")
+	}
+	if edited.Content != "Resolved" {
+		t.Errorf("Content = %q, want %q", edited.Content, "Resolved")
+	}
+}
+
+func TestWebhook_EditMessage_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "/webhooks/123/abc")
+	if _, err := webhook.EditMessage("1001", Message{Content: "Resolved"}); err == nil {
+		t.Error("EditMessage() error = nil, want error")
+	}
+}
+
+func TestWebhook_DeleteMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE request, got %s", r.Method)
+		}
+		if r.URL.Path != "/webhooks/123/abc/messages/1001" {
+			t.Errorf("Expected path /webhooks/123/abc/messages/1001, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "/webhooks/123/abc")
+	if err := webhook.DeleteMessage("1001"); err != nil {
+		t.Errorf("DeleteMessage() error = %v, want nil", err)
+	}
+}
+
+func TestWebhook_Thread(t *testing.T) {
+	var threadIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		threadIDs = append(threadIDs, r.URL.Query().Get("thread_id"))
+		switch r.Method {
+		case http.MethodPatch:
+			_, _ = w.Write([]byte(`{"id": "1001"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "/webhooks/123/abc")
+	thread := webhook.Thread("42")
+
+	if err := thread.Send(Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+	if _, err := thread.EditMessage("1001", Message{Content: "Test"}); err != nil {
+		t.Fatalf("EditMessage() error = %v, want nil", err)
+	}
+	if err := thread.DeleteMessage("1001"); err != nil {
+		t.Fatalf("DeleteMessage() error = %v, want nil", err)
+	}
+	if err := webhook.Send(Message{Content: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v, want nil", err)
+	}
+
+	want := []string{"42", "42", "42", ""}
+	if len(threadIDs) != len(want) {
+		t.Fatalf("requests = %d, want %d", len(threadIDs), len(want))
+	}
+	for i := range want {
+		if threadIDs[i] != want[i] {
+			t.Errorf("request %d thread_id = %q, want %q", i, threadIDs[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redacted replaces webhook tokens in logged URLs
+const redacted = "REDACTED"
+
+// officialHosts are the hosts that serve Discord's API
+var officialHosts = []string{
+	"discord.com",
+	"ptb.discord.com",
+	"canary.discord.com",
+	"discordapp.com",
+	"ptb.discordapp.com",
+	"canary.discordapp.com",
+}
+
+var (
+	// officialWebhookPath matches /api[/v{n}]/webhooks/{id}/{token}
+	officialWebhookPath = regexp.MustCompile(`^/api(?:/v\d+)?/webhooks/([^/]+)/([^/]+)/?$`)
+	// webhookPath matches /webhooks/{id}/{token} below a custom API base
+	webhookPath = regexp.MustCompile(`^/webhooks/([^/]+)/([^/]+)/?$`)
+	// webhookToken matches the token segment of any webhook URL
+	webhookToken = regexp.MustCompile(`(/webhooks/[^/?#]+/)[^/?#]+`)
+	// snowflake matches a Discord ID
+	snowflake = regexp.MustCompile(`^\d+$`)
+)
+
+// WebhookURL is a parsed Discord webhook URL
+type WebhookURL struct {
+	ID    string
+	Token string
+
+	url *url.URL
+}
+
+// ParseWebhookURL parses and validates a Discord webhook URL. Official Discord
+// hosts are always accepted over HTTPS on the default port; apiBase, if not
+// empty, additionally accepts webhook URLs below a custom API base such as a
+// local stand-in for Discord.
+func ParseWebhookURL(raw, apiBase string) (*WebhookURL, error) {
+	// Errors never include the path or query since they may hold the token
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", errors.Unwrap(err))
+	}
+
+	var match []string
+	switch {
+	case u.Scheme == "https" && u.Port() == "" && slices.Contains(officialHosts, u.Hostname()):
+		match = officialWebhookPath.FindStringSubmatch(u.Path)
+	case apiBase != "":
+		base, err := url.Parse(strings.TrimSuffix(apiBase, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Discord API base URL %q: %w", apiBase, err)
+		}
+		if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.Path, base.Path+"/") {
+			return nil, fmt.Errorf("webhook URL host %q is not a Discord host and the URL is not below %s", u.Host, apiBase)
+		}
+		match = webhookPath.FindStringSubmatch(strings.TrimPrefix(u.Path, base.Path))
+	default:
+		return nil, fmt.Errorf("webhook URL %s://%s is not a Discord host", u.Scheme, u.Host)
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("webhook URL path does not match /webhooks/{id}/{token}")
+	}
+	if !snowflake.MatchString(match[1]) {
+		return nil, fmt.Errorf("webhook URL has an invalid webhook ID %q", match[1])
+	}
+
+	return &WebhookURL{ID: match[1], Token: match[2], url: u}, nil
+}
+
+// String returns the full webhook URL, including the token
+func (u *WebhookURL) String() string {
+	return u.url.String()
+}
+
+// Redacted returns the webhook URL with the token hidden, safe for logging
+func (u *WebhookURL) Redacted() string {
+	return RedactURL(u.url.String())
+}
+
+// RedactURL hides the token of any webhook URL contained in s
+func RedactURL(s string) string {
+	return webhookToken.ReplaceAllString(s, "${1}"+redacted)
+}
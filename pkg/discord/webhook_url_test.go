@@ -0,0 +1,176 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseWebhookURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		apiBase   string
+		wantID    string
+		wantToken string
+		wantErr   bool
+	}{
+		{
+			name:      "discord.com",
+			raw:       "https://discord.com/api/webhooks/123/abc",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:      "versioned API path",
+			raw:       "https://discord.com/api/v10/webhooks/123/abc",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:      "discordapp.com",
+			raw:       "https://discordapp.com/api/webhooks/123/abc",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:      "canary",
+			raw:       "https://canary.discord.com/api/webhooks/123/abc",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:      "ptb",
+			raw:       "https://ptb.discord.com/api/webhooks/123/abc",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:      "custom API base",
+			raw:       "http://localhost:9000/api/webhooks/123/abc",
+			apiBase:   "http://localhost:9000/api/",
+			wantID:    "123",
+			wantToken: "abc",
+		},
+		{
+			name:    "unknown host",
+			raw:     "https://example.com/api/webhooks/123/abc",
+			wantErr: true,
+		},
+		{
+			name:    "not below custom API base",
+			raw:     "http://localhost:9001/api/webhooks/123/abc",
+			apiBase: "http://localhost:9000/api",
+			wantErr: true,
+		},
+		{
+			name:    "plain http to Discord",
+			raw:     "http://discord.com/api/webhooks/123/abc",
+			wantErr: true,
+		},
+		{
+			name:    "port on Discord host",
+			raw:     "https://discord.com:8443/api/webhooks/123/abc",
+			wantErr: true,
+		},
+		{
+			name:    "missing token",
+			raw:     "https://discord.com/api/webhooks/123",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric ID",
+			raw:     "https://discord.com/api/webhooks/xyz/abc",
+			wantErr: true,
+		},
+		{
+			name:    "unparsable URL",
+			raw:     "https://discord.com/api/webhooks/123/abc%zz",
+			wantErr: true,
+		},
+		{
+			name:    "typo in path",
+			raw:     "https://discord.com/api/webhook/123/abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWebhookURL(tt.raw, tt.apiBase)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseWebhookURL() = %+v, want error", got)
+				}
+				if strings.Contains(err.Error(), "abc") {
+					t.Errorf("ParseWebhookURL() error %q leaks the token", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWebhookURL() error = %v", err)
+			}
+			if got.ID != tt.wantID || got.Token != tt.wantToken {
+				t.Errorf("ParseWebhookURL() = %s/%s, want %s/%s", got.ID, got.Token, tt.wantID, tt.wantToken)
+			}
+			if got.String() != tt.raw {
+				t.Errorf("String() = %q, want %q", got.String(), tt.raw)
+			}
+		})
+	}
+}
+
+func TestWebhookURL_Redacted(t *testing.T) {
+	webhookURL, err := ParseWebhookURL("https://discord.com/api/webhooks/123/secret-token", "")
+	if err != nil {
+		t.Fatalf("ParseWebhookURL() error = %v", err)
+	}
+
+	want := "https://discord.com/api/webhooks/123/REDACTED"
+	if got := webhookURL.Redacted(); got != want {
+		t.Errorf("Redacted() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	got := RedactURL(`Post "https://discord.com/api/webhooks/123/secret/messages/1?wait=true": EOF`)
+	want := `Post "https://discord.com/api/webhooks/123/REDACTED/messages/1?wait=true": EOF`
+	if got != want {
+		t.Errorf("RedactURL() = %q, want %q", got, want)
+	}
+}
+
+func TestWebhook_Send_RedactsTokenInErrors(t *testing.T) {
+	webhook := NewWebhook("http://127.0.0.1:1/api/webhooks/123/secret-token")
+	webhook.Retry.MaxAttempts = 1
+
+	err := webhook.Send(Message{Content: "Test"})
+	if err == nil {
+		t.Fatal("Send() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Send() error %q leaks the token", err)
+	}
+}
+
+func TestWebhook_Info(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		_, _ = w.Write([]byte(`{"id": "123", "type": 1, "name": "Alerts", "guild_id": "456", "channel_id": "789"}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL + "/webhooks/123/abc")
+	info, err := webhook.Info(context.Background())
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	if info.Name != "Alerts" || info.GuildID != "456" || info.ChannelID != "789" {
+		t.Errorf("Info() = %+v, want webhook Alerts in guild 456, channel 789", info)
+	}
+}
@@ -22,4 +22,9 @@ type Alert struct {
 	EndsAt       time.Time         `json:"endsAt"`
 	GeneratorURL string            `json:"generatorURL"`
 	Fingerprint  string            `json:"fingerprint"`
+	ImageURL     string            `json:"imageURL"`
+	DashboardURL string            `json:"dashboardURL"`
+	PanelURL     string            `json:"panelURL"`
+	// Values holds the result of each query and expression by refID
+	Values map[string]float64 `json:"values"`
 }
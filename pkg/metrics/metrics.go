@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -43,6 +44,22 @@ var (
 		[]string{"status"},
 	)
 
+	WebhookDiscordErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_discord_errors_total",
+			Help: "Total number of Discord API errors by HTTP status and Discord error code",
+		},
+		[]string{"status", "code"},
+	)
+
+	WebhookDiscordRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_discord_retries_total",
+			Help: "Total number of retried Discord webhook requests",
+		},
+		[]string{"reason"},
+	)
+
 	WebhookDiscordSendDuration = promauto.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "webhook_discord_send_duration_seconds",
@@ -89,6 +106,16 @@ func RecordDiscordSend(success bool, duration time.Duration) {
 	WebhookDiscordSendDuration.Observe(duration.Seconds())
 }
 
+// RecordDiscordError increments the Discord API error counter
+func RecordDiscordError(status, code int) {
+	WebhookDiscordErrorsTotal.WithLabelValues(strconv.Itoa(status), strconv.Itoa(code)).Inc()
+}
+
+// RecordDiscordRetry increments the Discord retry counter
+func RecordDiscordRetry(reason string) {
+	WebhookDiscordRetriesTotal.WithLabelValues(reason).Inc()
+}
+
 // RecordAlert increments the alert counter
 func RecordAlert(alertStatus, severity string) {
 	AlertsReceivedTotal.WithLabelValues(alertStatus, severity).Inc()
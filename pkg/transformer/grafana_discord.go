@@ -2,7 +2,10 @@ package transformer
 
 import (
 	"fmt"
+	"maps"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/pretty-discord-alerts/pkg/discord"
 	"github.com/pretty-discord-alerts/pkg/grafana"
@@ -15,141 +18,338 @@ const (
 	colorNotification = 9807270  // Gray
 )
 
-// GrafanaToDiscord transforms a Grafana webhook payload to Discord messages (one per alert)
-func GrafanaToDiscord(payload *grafana.WebhookPayload) []discord.Message {
+// grafanaIconURL is shown next to the footer
+const grafanaIconURL = "https://grafana.com/static/assets/img/fav32.png"
+
+// Option configures how Grafana payloads are transformed
+type Option func(*options)
+
+type options struct {
+	threadNames         bool
+	grouping            bool
+	layout              Layout
+	templates           *Templates
+	severity            *SeverityProfile
+	labels              *LabelFields
+	silence             SilenceOptions
+	hiddenRefs          []string
+	markdownAnnotations []string
+	allowedMentions     []string
+}
+
+func defaultOptions() options {
+	return options{templates: defaultTemplates, severity: DefaultSeverityProfile()}
+}
+
+// Layout selects how the alerts of a notification are rendered
+type Layout string
+
+const (
+	// LayoutAlert renders one embed per alert
+	LayoutAlert Layout = "alert"
+	// LayoutSummary renders a single summary embed per notification, with one
+	// line per alert
+	LayoutSummary Layout = "summary"
+)
+
+// ParseLayout parses a layout name
+func ParseLayout(s string) (Layout, error) {
+	switch layout := Layout(s); layout {
+	case LayoutAlert, LayoutSummary:
+		return layout, nil
+	}
+	return "", fmt.Errorf("unknown layout %q, want %q or %q", s, LayoutAlert, LayoutSummary)
+}
+
+// WithThreadNames names a forum post after each alert so that every alert
+// gets its own thread when posting to a forum channel
+func WithThreadNames() Option {
+	return func(o *options) {
+		o.threadNames = true
+	}
+}
+
+// WithGrouping packs all alerts of a notification into as few messages as
+// possible, up to 10 embeds per message within Discord's size limits
+func WithGrouping() Option {
+	return func(o *options) {
+		o.grouping = true
+	}
+}
+
+// WithTemplates renders messages with the given templates instead of the
+// built-in ones
+func WithTemplates(templates *Templates) Option {
+	return func(o *options) {
+		o.templates = templates
+	}
+}
+
+// WithSeverityProfile selects the severity label and the look of each level
+// instead of the built-in profile
+func WithSeverityProfile(profile *SeverityProfile) Option {
+	return func(o *options) {
+		o.severity = profile
+	}
+}
+
+// WithLabelFields renders the selected alert labels as embed fields after the
+// alert details
+func WithLabelFields(labels LabelFields) Option {
+	return func(o *options) {
+		o.labels = &labels
+	}
+}
+
+// WithSilenceOptions configures the silence links
+func WithSilenceOptions(silence SilenceOptions) Option {
+	return func(o *options) {
+		o.silence = silence
+	}
+}
+
+// WithHiddenRefIDs hides the query values of the given refIDs, or glob
+// patterns of them, such as the threshold expression
+func WithHiddenRefIDs(refIDs ...string) Option {
+	return func(o *options) {
+		o.hiddenRefs = refIDs
+	}
+}
+
+// WithMarkdownAnnotations leaves the given annotations, or glob patterns of
+// them, unescaped since they are written as markdown on purpose. All other
+// labels and annotations are escaped to display literally.
+func WithMarkdownAnnotations(names ...string) Option {
+	return func(o *options) {
+		o.markdownAnnotations = names
+	}
+}
+
+// WithAllowedMentions lets the given mention types ("roles", "users" or
+// "everyone") in the content ping. By default no mention pings anyone.
+func WithAllowedMentions(parse ...string) Option {
+	return func(o *options) {
+		o.allowedMentions = parse
+	}
+}
+
+// WithLayout selects how alerts are rendered. The default is LayoutAlert.
+func WithLayout(layout Layout) Option {
+	return func(o *options) {
+		o.layout = layout
+	}
+}
+
+// GrafanaToDiscord transforms a Grafana webhook payload to Discord messages
+// (one per alert, grouped with WithGrouping, or a summary with LayoutSummary)
+func GrafanaToDiscord(payload *grafana.WebhookPayload, opts ...Option) []discord.Message {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.layout == LayoutSummary {
+		return summaryMessages(payload, o)
+	}
+	if o.grouping {
+		return groupedMessages(payload, o)
+	}
+
 	messages := make([]discord.Message, 0, len(payload.Alerts))
 
+	escaped := escapePayload(payload, o.markdownAnnotations)
 	for _, alert := range payload.Alerts {
-		// Determine severity and color
-		severity := alert.Labels["severity"]
-		color := colorResolved
-		
-		// Notification/info severity always uses gray color
-		if severity == "notification" || severity == "info" {
-			color = colorNotification
-		} else if alert.Status == "firing" {
-			if severity == "critical" {
-				color = colorFiring
-			} else {
-				color = colorWarning
-			}
+		data := newTemplateData(escaped, alert, o)
+		msg := newMessage(data, o)
+		msg.Embeds = []discord.Embed{buildEmbed(data, o)}
+		if o.threadNames {
+			msg.ThreadName = getThreadName(alert)
 		}
 
-		// Get alerting URL from external URL
-		alertingURL := payload.ExternalURL
-		if alertingURL != "" {
-			alertingURL = strings.TrimSuffix(alertingURL, "/") + "/alerting/list"
-		}
+		messages = append(messages, msg)
+	}
 
-		// Build title
-		title := getAlertTitle(alert)
-
-		// Build field value
-		fieldValue := buildFieldValue(alert, payload.ExternalURL)
-
-		embed := discord.Embed{
-			Title:       title,
-			Description: "",
-			Type:        "rich",
-			URL:         alertingURL,
-			Color:       color,
-			Fields: []discord.EmbedField{
-				{
-					Name:   alert.Labels["alertname"],
-					Value:  fieldValue,
-					Inline: false,
-				},
-			},
-			Footer: &discord.EmbedFooter{
-				Text:    "Grafana v12.3.2",
-				IconURL: "https://grafana.com/static/assets/img/fav32.png",
-			},
-		}
+	return messages
+}
 
-		messages = append(messages, discord.Message{
-			Username: "Grafana",
-			Embeds:   []discord.Embed{embed},
-		})
+// groupedMessages packs the embeds of all alerts into as few messages as
+// Discord's limits allow
+func groupedMessages(payload *grafana.WebhookPayload, o options) []discord.Message {
+	if len(payload.Alerts) == 0 {
+		return nil
 	}
 
-	return messages
+	escaped := escapePayload(payload, o.markdownAnnotations)
+
+	// Message-level parts are rendered for the first alert
+	msg := newMessage(newTemplateData(escaped, payload.Alerts[0], o), o)
+	msg.Embeds = make([]discord.Embed, 0, len(payload.Alerts))
+	for _, alert := range payload.Alerts {
+		msg.Embeds = append(msg.Embeds, buildEmbed(newTemplateData(escaped, alert, o), o))
+	}
+	if o.threadNames {
+		// Left whole so that SendSplit posts the overflow into the new forum post
+		msg.ThreadName = getThreadName(grafana.Alert{Labels: payload.CommonLabels})
+		return []discord.Message{msg}
+	}
+
+	return msg.Split()
 }
 
-func getAlertTitle(alert grafana.Alert) string {
-	severity := alert.Labels["severity"]
-	
-	// Notification/info severity always shows info emoji regardless of status
-	if severity == "notification" || severity == "info" {
-		return "ℹ️ Notification"
+// newMessage starts a message with the rendered username and content. No
+// mention pings anyone unless allowed with WithAllowedMentions.
+func newMessage(data *TemplateData, o options) discord.Message {
+	return discord.Message{
+		Username:        o.templates.execute(templateUsername, data),
+		Content:         o.templates.execute(templateContent, data),
+		AllowedMentions: &discord.AllowedMentions{Parse: append([]string{}, o.allowedMentions...)},
+	}
+}
+
+// buildEmbed renders a single alert as a Discord embed
+func buildEmbed(data *TemplateData, o options) discord.Embed {
+	embed := discord.Embed{
+		Title:       o.templates.execute(templateTitle, data),
+		Description: o.templates.execute(templateDescription, data),
+		Type:        "rich",
+		URL:         data.AlertingURL,
+		Color:       int(data.Severity.Color),
+		Timestamp:   getTimestamp(data.Alert),
+	}
+
+	name := o.templates.execute(templateFieldName, data)
+	value := renderFieldValue(data, o)
+	if name != "" || value != "" {
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:   name,
+			Value:  value,
+			Inline: false,
+		})
 	}
-	
-	if alert.Status == "firing" {
-		if severity == "critical" {
-			return "🔥 Critical Alert Firing"
+
+	embed.Fields = append(embed.Fields, valueFields(data.Values, discord.MaxEmbedFields-len(embed.Fields))...)
+
+	if data.Alert.ImageURL != "" {
+		embed.Image = &discord.EmbedImage{URL: data.Alert.ImageURL}
+	}
+
+	if o.labels != nil {
+		embed.Fields = append(embed.Fields, o.labels.fields(data.Alert.Labels, discord.MaxEmbedFields-len(embed.Fields))...)
+	}
+
+	if footer := o.templates.execute(templateFooter, data); footer != "" {
+		embed.Footer = &discord.EmbedFooter{
+			Text:    footer,
+			IconURL: grafanaIconURL,
 		}
-		return "⚠️ Warning Alert Firing"
 	}
-	return "✅ Alert Resolved"
+
+	return embed
 }
 
-func buildFieldValue(alert grafana.Alert, externalURL string) string {
-	var value string
+// renderFieldValue renders the alert details within Discord's field limit.
+// Long description and summary annotations are shortened, in that order, so
+// that the status and links after them are not cut off.
+func renderFieldValue(data *TemplateData, o options) string {
+	value := o.templates.execute(templateFieldValue, data)
+	over := utf8.RuneCountInString(value) - discord.MaxFieldValueLength
+	if over <= 0 {
+		return value
+	}
 
-	if summary := alert.Annotations["summary"]; summary != "" {
-		value += fmt.Sprintf("**Summary:** %s\n", summary)
+	shortened := *data
+	shortened.Alert.Annotations = maps.Clone(data.Alert.Annotations)
+	for _, key := range []string{"description", "summary"} {
+		text := shortened.Alert.Annotations[key]
+		length := utf8.RuneCountInString(text)
+		if over <= 0 || length == 0 {
+			continue
+		}
+		shortened.Alert.Annotations[key] = truncateEscaped(length-over, text)
+		over -= length - utf8.RuneCountInString(shortened.Alert.Annotations[key])
 	}
-	if description := alert.Annotations["description"]; description != "" {
-		value += fmt.Sprintf("**Description:** %s\n", description)
+	return o.templates.execute(templateFieldValue, &shortened)
+}
+
+// truncateEscaped shortens markdown-escaped text like truncateText, without
+// leaving a backslash that would escape the ellipsis
+func truncateEscaped(limit int, s string) string {
+	short := truncateText(limit, s)
+	if short == s || short == "" {
+		return short
 	}
-	if values := alert.Annotations["values"]; values != "" {
-		value += fmt.Sprintf("**Query Results:** %s\n", values)
+	return strings.TrimRight(strings.TrimSuffix(short, "…"), `\`) + "…"
+}
+
+// newTemplateData collects what the templates need to render an alert. The
+// payload is expected to be escaped already; the alert is escaped here while
+// its links are built from the original.
+func newTemplateData(payload *grafana.WebhookPayload, alert grafana.Alert, o options) *TemplateData {
+	data := &TemplateData{
+		Payload:     payload,
+		Alert:       escapeAlert(alert, o.markdownAnnotations),
+		Severity:    o.severity.style(alert),
+		Values:      getValues(alert, o.hiddenRefs),
+		Duration:    getDuration(alert),
+		AlertingURL: getAlertingURL(payload.ExternalURL),
 	}
-	if namespace := alert.Labels["namespace"]; namespace != "" {
-		value += fmt.Sprintf("**Namespace:** %s\n", namespace)
+	if payload.ExternalURL != "" {
+		data.SilenceURL = buildSilenceURL(payload.ExternalURL, alert, o.silence)
 	}
 
-	// Don't show status for notification/info severity
-	severity := alert.Labels["severity"]
-	if severity != "notification" && severity != "info" {
-		emoji := "🔴"
-		status := "Firing"
-		if alert.Status == "resolved" {
-			emoji = "✅"
-			status = "Resolved"
+	for _, link := range []Link{
+		{Name: "View Source", URL: alert.GeneratorURL},
+		{Name: "Dashboard", URL: alert.DashboardURL},
+		{Name: "Panel", URL: alert.PanelURL},
+		{Name: "Silence", URL: data.SilenceURL},
+	} {
+		if link.URL != "" {
+			data.Links = append(data.Links, link)
 		}
-		value += fmt.Sprintf("**Status:** %s %s\n", emoji, status)
 	}
+	return data
+}
 
-	// Add action links
-	var links []string
-	if alert.GeneratorURL != "" {
-		links = append(links, fmt.Sprintf("[View Source](%s)", alert.GeneratorURL))
+// getTimestamp returns when a firing alert started or a resolved alert ended,
+// if known
+func getTimestamp(alert grafana.Alert) string {
+	t := alert.StartsAt
+	if alert.Status == "resolved" {
+		t = alert.EndsAt
 	}
-	if externalURL != "" {
-		silenceURL := buildSilenceURL(externalURL, alert.Labels)
-		links = append(links, fmt.Sprintf("[Silence](%s)", silenceURL))
+	if t.IsZero() {
+		return ""
 	}
-	
-	if len(links) > 0 {
-		value += "\n" + strings.Join(links, " • ")
+	return t.UTC().Format(time.RFC3339)
+}
+
+// getDuration returns how long a resolved alert was firing, or zero if
+// unknown
+func getDuration(alert grafana.Alert) time.Duration {
+	if alert.Status != "resolved" || alert.StartsAt.IsZero() || alert.EndsAt.Before(alert.StartsAt) {
+		return 0
 	}
+	return alert.EndsAt.Sub(alert.StartsAt)
+}
 
-	return value
+// getAlertingURL returns the link to Grafana's alert list, if the external
+// URL is known
+func getAlertingURL(externalURL string) string {
+	if externalURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(externalURL, "/") + "/alerting/list"
 }
 
-func buildSilenceURL(externalURL string, labels map[string]string) string {
-	baseURL := strings.TrimSuffix(externalURL, "/")
-	silenceURL := baseURL + "/alerting/silence/new?alertmanager=grafana"
-	
-	for key, value := range labels {
-		// URL encode the matcher
-		silenceURL += fmt.Sprintf("&matcher=%s%%3D%s", key, strings.ReplaceAll(value, " ", "+"))
+// getThreadName derives a forum post name from the alert name and namespace
+func getThreadName(alert grafana.Alert) string {
+	name := alert.Labels["alertname"]
+	if name == "" {
+		name = "Grafana Alert"
 	}
-	
-	// Add orgId if present in external URL
-	if strings.Contains(externalURL, "orgId=") {
-		silenceURL += "&orgId=1"
+	if namespace := alert.Labels["namespace"]; namespace != "" {
+		name += " - " + namespace
 	}
-	
-	return silenceURL
+
+	return truncateText(discord.MaxThreadNameLength, name)
 }
@@ -1,9 +1,14 @@
 package transformer
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/pretty-discord-alerts/pkg/discord"
 	"github.com/pretty-discord-alerts/pkg/grafana"
 )
 
@@ -213,7 +218,7 @@ func TestGrafanaToDiscord(t *testing.T) {
 	}
 }
 
-func TestGetAlertTitle(t *testing.T) {
+func TestDefaultTemplates_Title(t *testing.T) {
 	tests := []struct {
 		name  string
 		alert grafana.Alert
@@ -270,15 +275,302 @@ func TestGetAlertTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getAlertTitle(tt.alert)
+			got := renderDefault(templateTitle, tt.alert, "")
 			if got != tt.want {
-				t.Errorf("getAlertTitle() = %q, want %q", got, tt.want)
+				t.Errorf("title = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestBuildFieldValue(t *testing.T) {
+func TestGrafanaToDiscord_ThreadNames(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		Status: "firing",
+		Alerts: []grafana.Alert{
+			{
+				Status: "firing",
+				Labels: map[string]string{
+					"alertname": "HighCPU",
+					"namespace": "production",
+				},
+			},
+		},
+	}
+
+	msgs := GrafanaToDiscord(payload)
+	if msgs[0].ThreadName != "" {
+		t.Errorf("thread name = %q, want empty without WithThreadNames", msgs[0].ThreadName)
+	}
+
+	msgs = GrafanaToDiscord(payload, WithThreadNames())
+	if msgs[0].ThreadName != "HighCPU - production" {
+		t.Errorf("thread name = %q, want %q", msgs[0].ThreadName, "HighCPU - production")
+	}
+}
+
+func TestGrafanaToDiscord_Grouping(t *testing.T) {
+	newPayload := func(n int) *grafana.WebhookPayload {
+		payload := &grafana.WebhookPayload{
+			Status:       "firing",
+			CommonLabels: map[string]string{"alertname": "HighCPU", "namespace": "production"},
+		}
+		for i := 0; i < n; i++ {
+			payload.Alerts = append(payload.Alerts, grafana.Alert{
+				Status: "firing",
+				Labels: map[string]string{
+					"alertname": "HighCPU",
+					"namespace": "production",
+					"instance":  fmt.Sprintf("node-%d", i),
+				},
+				Annotations: map[string]string{"summary": "CPU usage is high"},
+			})
+		}
+		return payload
+	}
+
+	tests := []struct {
+		name       string
+		alerts     int
+		wantCounts []int
+	}{
+		{name: "no alerts", alerts: 0, wantCounts: nil},
+		{name: "single alert", alerts: 1, wantCounts: []int{1}},
+		{name: "full message", alerts: 10, wantCounts: []int{10}},
+		{name: "overflow", alerts: 25, wantCounts: []int{10, 10, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := GrafanaToDiscord(newPayload(tt.alerts), WithGrouping())
+			if len(msgs) != len(tt.wantCounts) {
+				t.Fatalf("GrafanaToDiscord() returned %d messages, want %d", len(msgs), len(tt.wantCounts))
+			}
+			for i, msg := range msgs {
+				if len(msg.Embeds) != tt.wantCounts[i] {
+					t.Errorf("message %d has %d embeds, want %d", i, len(msg.Embeds), tt.wantCounts[i])
+				}
+				if err := msg.Validate(); err != nil {
+					t.Errorf("message %d is invalid: %v", i, err)
+				}
+			}
+		})
+	}
+
+	t.Run("total length", func(t *testing.T) {
+		payload := newPayload(10)
+		for i := range payload.Alerts {
+			payload.Alerts[i].Annotations["description"] = strings.Repeat("x", 900)
+		}
+		msgs := GrafanaToDiscord(payload, WithGrouping())
+		if len(msgs) < 2 {
+			t.Fatalf("GrafanaToDiscord() returned %d messages, want the embeds spread over several", len(msgs))
+		}
+		embeds := 0
+		for i, msg := range msgs {
+			if err := msg.Validate(); err != nil {
+				t.Errorf("message %d is invalid: %v", i, err)
+			}
+			embeds += len(msg.Embeds)
+		}
+		if embeds != 10 {
+			t.Errorf("messages hold %d embeds, want 10", embeds)
+		}
+	})
+
+	t.Run("thread name", func(t *testing.T) {
+		msgs := GrafanaToDiscord(newPayload(25), WithGrouping(), WithThreadNames())
+		if len(msgs) != 1 {
+			t.Fatalf("GrafanaToDiscord() returned %d messages, want 1 for SendSplit to post into the thread", len(msgs))
+		}
+		if msgs[0].ThreadName != "HighCPU - production" {
+			t.Errorf("thread name = %q, want %q", msgs[0].ThreadName, "HighCPU - production")
+		}
+		if parts := msgs[0].Split(); len(parts) != 3 {
+			t.Errorf("Split() returned %d parts, want 3", len(parts))
+		}
+	})
+}
+
+func TestGrafanaToDiscord_Timestamps(t *testing.T) {
+	startsAt := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(time.Hour + 12*time.Minute)
+
+	tests := []struct {
+		name          string
+		alert         grafana.Alert
+		wantTimestamp string
+		wantStatus    string
+	}{
+		{
+			name:          "firing",
+			alert:         grafana.Alert{Status: "firing", StartsAt: startsAt},
+			wantTimestamp: "2026-02-02T10:00:00Z",
+			wantStatus:    "**Status:** 🔴 Firing since <t:1770026400:R>",
+		},
+		{
+			name:          "resolved",
+			alert:         grafana.Alert{Status: "resolved", StartsAt: startsAt, EndsAt: endsAt},
+			wantTimestamp: "2026-02-02T11:12:00Z",
+			wantStatus:    "**Status:** ✅ Resolved <t:1770030720:R> after 1h 12m",
+		},
+		{
+			name:          "unknown times",
+			alert:         grafana.Alert{Status: "firing"},
+			wantTimestamp: "",
+			wantStatus:    "**Status:** 🔴 Firing\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.alert.Labels = map[string]string{"alertname": "HighCPU"}
+			embed := GrafanaToDiscord(&grafana.WebhookPayload{Alerts: []grafana.Alert{tt.alert}})[0].Embeds[0]
+
+			if embed.Timestamp != tt.wantTimestamp {
+				t.Errorf("timestamp = %q, want %q", embed.Timestamp, tt.wantTimestamp)
+			}
+			if value := embed.Fields[0].Value + "\n"; !strings.Contains(value, tt.wantStatus) {
+				t.Errorf("field value = %q, want it to contain %q", value, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGrafanaToDiscord_ImageAndLinks(t *testing.T) {
+	body := `{
+		"externalURL": "https://grafana.example.com/",
+		"alerts": [{
+			"status": "firing",
+			"labels": {"alertname": "HighCPU"},
+			"generatorURL": "https://grafana.example.com/alerting/grafana/abc/view",
+			"imageURL": "https://grafana.example.com/public/img/attachments/abc.png",
+			"dashboardURL": "https://grafana.example.com/d/node",
+			"panelURL": "https://grafana.example.com/d/node?viewPanel=2"
+		}]
+	}`
+	var payload grafana.WebhookPayload
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	embed := GrafanaToDiscord(&payload)[0].Embeds[0]
+	if embed.Image == nil || embed.Image.URL != "https://grafana.example.com/public/img/attachments/abc.png" {
+		t.Errorf("image = %+v, want the alert screenshot", embed.Image)
+	}
+
+	want := "\n\n[View Source](https://grafana.example.com/alerting/grafana/abc/view)" +
+		" • [Dashboard](https://grafana.example.com/d/node)" +
+		" • [Panel](https://grafana.example.com/d/node?viewPanel=2)" +
+		" • [Silence](https://grafana.example.com/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHighCPU)"
+	if value := embed.Fields[0].Value; !strings.HasSuffix(value, want) {
+		t.Errorf("field value = %q, want it to end with %q", value, want)
+	}
+
+	payload.Alerts[0].ImageURL = ""
+	if embed := GrafanaToDiscord(&payload)[0].Embeds[0]; embed.Image != nil {
+		t.Errorf("image = %+v, want none without a screenshot", embed.Image)
+	}
+}
+
+func TestGrafanaToDiscord_LongDescription(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		ExternalURL: "https://grafana.example.com",
+		Alerts: []grafana.Alert{
+			{
+				Status: "firing",
+				Labels: map[string]string{"alertname": "HighCPU"},
+				Annotations: map[string]string{
+					"summary":     "CPU usage is high",
+					"description": strings.Repeat("x", 2000),
+				},
+				GeneratorURL: "https://grafana.example.com/alerting/grafana/abc/view",
+			},
+		},
+	}
+
+	for _, messages := range [][]discord.Message{
+		GrafanaToDiscord(payload),
+		GrafanaToDiscord(payload, WithGrouping()),
+	} {
+		value := messages[0].Split()[0].Embeds[0].Fields[0].Value
+		if n := utf8.RuneCountInString(value); n > discord.MaxFieldValueLength {
+			t.Errorf("field value has %d characters, want at most %d", n, discord.MaxFieldValueLength)
+		}
+		for _, want := range []string{"**Summary:** CPU usage is high", "x…\n", "**Status:** 🔴 Firing", "[View Source](", "[Silence]("} {
+			if !strings.Contains(value, want) {
+				t.Errorf("field value = %q, want it to contain %q", value, want)
+			}
+		}
+	}
+
+	// Escaped text is never cut between a backslash and what it escapes
+	for _, n := range []int{1999, 2000} {
+		payload.Alerts[0].Annotations["description"] = strings.Repeat("*", n)
+		value := GrafanaToDiscord(payload)[0].Embeds[0].Fields[0].Value
+		if strings.Contains(value, `\…`) || !strings.Contains(value, "[Silence](") {
+			t.Errorf("field value = %q, want escaped text shortened before a backslash and the links kept", value)
+		}
+	}
+}
+
+func TestTruncateEscaped(t *testing.T) {
+	tests := []struct {
+		limit int
+		in    string
+		want  string
+	}{
+		{10, `short \*`, `short \*`},
+		{4, `ab\*cd`, `ab…`},
+		{5, `ab\\\\cd`, `ab…`},
+		{0, `ab\*cd`, ``},
+	}
+
+	for _, tt := range tests {
+		if got := truncateEscaped(tt.limit, tt.in); got != tt.want {
+			t.Errorf("truncateEscaped(%d, %q) = %q, want %q", tt.limit, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetThreadName(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name:   "alertname only",
+			labels: map[string]string{"alertname": "HighCPU"},
+			want:   "HighCPU",
+		},
+		{
+			name:   "alertname and namespace",
+			labels: map[string]string{"alertname": "HighCPU", "namespace": "production"},
+			want:   "HighCPU - production",
+		},
+		{
+			name:   "missing alertname",
+			labels: map[string]string{},
+			want:   "Grafana Alert",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getThreadName(grafana.Alert{Labels: tt.labels})
+			if got != tt.want {
+				t.Errorf("getThreadName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	long := getThreadName(grafana.Alert{Labels: map[string]string{"alertname": strings.Repeat("é", 150)}})
+	if n := utf8.RuneCountInString(long); n != discord.MaxThreadNameLength {
+		t.Errorf("getThreadName() length = %d, want %d", n, discord.MaxThreadNameLength)
+	}
+}
+
+func TestDefaultTemplates_FieldValue(t *testing.T) {
 	tests := []struct {
 		name        string
 		alert       grafana.Alert
@@ -340,29 +632,34 @@ func TestBuildFieldValue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			value := buildFieldValue(tt.alert, tt.externalURL)
+			value := renderDefault(templateFieldValue, tt.alert, tt.externalURL)
 
 			if value == "" {
-				t.Error("buildFieldValue() returned empty string")
+				t.Error("field value is empty")
 			}
 
 			// Check that expected fields are included
 			for _, exp := range tt.wantStrings {
 				if !contains(value, exp) {
-					t.Errorf("buildFieldValue() missing %q in output: %q", exp, value)
+					t.Errorf("field value missing %q in output: %q", exp, value)
 				}
 			}
 
 			// Check that unwanted fields are not included
 			for _, unwanted := range tt.dontWant {
 				if contains(value, unwanted) {
-					t.Errorf("buildFieldValue() should not contain %q in output: %q", unwanted, value)
+					t.Errorf("field value should not contain %q in output: %q", unwanted, value)
 				}
 			}
 		})
 	}
 }
 
+// renderDefault executes a built-in template for the alert
+func renderDefault(name string, alert grafana.Alert, externalURL string) string {
+	return defaultTemplates.execute(name, newTemplateData(&grafana.WebhookPayload{ExternalURL: externalURL}, alert, defaultOptions()))
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsInString(s, substr))
 }
@@ -0,0 +1,86 @@
+package transformer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+)
+
+// LabelFields selects the alert labels rendered as embed fields
+type LabelFields struct {
+	// Include lists glob patterns of the labels to show, e.g. "instance" or
+	// "k8s_*". Labels are ordered by the first pattern they match, then by
+	// name. When empty, all labels are shown ordered by name.
+	Include []string
+	// Exclude lists glob patterns of labels to hide, even if included
+	Exclude []string
+	// Names maps label names to the names displayed for them
+	Names map[string]string
+	// Inline lays the fields out side by side
+	Inline bool
+}
+
+// Validate checks the glob patterns
+func (l *LabelFields) Validate() error {
+	for _, pattern := range append(append([]string(nil), l.Include...), l.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid label pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// fields renders the selected labels as at most limit embed fields
+func (l *LabelFields) fields(labels map[string]string, limit int) []discord.EmbedField {
+	type selected struct {
+		name string
+		rank int
+	}
+
+	var keys []selected
+	for name, value := range labels {
+		if value == "" || matchAny(l.Exclude, name) >= 0 {
+			continue
+		}
+		rank := 0
+		if len(l.Include) > 0 {
+			if rank = matchAny(l.Include, name); rank < 0 {
+				continue
+			}
+		}
+		keys = append(keys, selected{name: name, rank: rank})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rank != keys[j].rank {
+			return keys[i].rank < keys[j].rank
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	keys = keys[:min(len(keys), max(limit, 0))]
+	fields := make([]discord.EmbedField, 0, len(keys))
+	for _, key := range keys {
+		name := key.name
+		if display := l.Names[key.name]; display != "" {
+			name = display
+		}
+		fields = append(fields, discord.EmbedField{
+			Name:   name,
+			Value:  labels[key.name],
+			Inline: l.Inline,
+		})
+	}
+	return fields
+}
+
+// matchAny returns the index of the first pattern matching name, or -1
+func matchAny(patterns []string, name string) int {
+	for i, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,95 @@
+package transformer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func TestLabelFields(t *testing.T) {
+	labels := map[string]string{
+		"alertname":      "HighCPU",
+		"instance":       "node-1:9100",
+		"pod":            "api-0",
+		"cluster":        "eu-1",
+		"job":            "node",
+		"grafana_folder": "Infra",
+		"__alert_rule":   "abc",
+		"empty":          "",
+	}
+
+	tests := []struct {
+		name   string
+		fields LabelFields
+		want   []string
+	}{
+		{
+			name:   "all labels by name",
+			fields: LabelFields{},
+			want:   []string{"__alert_rule=abc", "alertname=HighCPU", "cluster=eu-1", "grafana_folder=Infra", "instance=node-1:9100", "job=node", "pod=api-0"},
+		},
+		{
+			name:   "include order",
+			fields: LabelFields{Include: []string{"instance", "pod", "cluster"}},
+			want:   []string{"instance=node-1:9100", "pod=api-0", "cluster=eu-1"},
+		},
+		{
+			name:   "glob include ordered by name within a pattern",
+			fields: LabelFields{Include: []string{"job", "*"}, Exclude: []string{"alertname", "__*"}},
+			want:   []string{"job=node", "cluster=eu-1", "grafana_folder=Infra", "instance=node-1:9100", "pod=api-0"},
+		},
+		{
+			name:   "exclude wins over include",
+			fields: LabelFields{Include: []string{"instance", "pod"}, Exclude: []string{"p*"}},
+			want:   []string{"instance=node-1:9100"},
+		},
+		{
+			name:   "display names",
+			fields: LabelFields{Include: []string{"grafana_folder", "instance"}, Names: map[string]string{"grafana_folder": "Folder"}},
+			want:   []string{"Folder=Infra", "instance=node-1:9100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, field := range tt.fields.fields(labels, discord.MaxEmbedFields) {
+				got = append(got, field.Name+"="+field.Value)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelFields_Limit(t *testing.T) {
+	labels := make(map[string]string)
+	for i := 0; i < 40; i++ {
+		labels[fmt.Sprintf("label_%02d", i)] = "value"
+	}
+
+	payload := &grafana.WebhookPayload{Alerts: []grafana.Alert{{Status: "firing", Labels: labels}}}
+	embed := GrafanaToDiscord(payload, WithLabelFields(LabelFields{Inline: true}))[0].Embeds[0]
+
+	if len(embed.Fields) != discord.MaxEmbedFields {
+		t.Fatalf("embed has %d fields, want %d", len(embed.Fields), discord.MaxEmbedFields)
+	}
+	if embed.Fields[0].Inline {
+		t.Error("alert details field is inline, want full width")
+	}
+	if !embed.Fields[1].Inline || embed.Fields[1].Name != "label_00" {
+		t.Errorf("first label field = %+v, want inline label_00", embed.Fields[1])
+	}
+}
+
+func TestLabelFields_Validate(t *testing.T) {
+	if err := (&LabelFields{Include: []string{"k8s_*", "instance"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&LabelFields{Exclude: []string{"[a-"}}).Validate(); err == nil {
+		t.Error("Validate() with a bad pattern succeeded, want error")
+	}
+}
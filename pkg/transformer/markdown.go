@@ -0,0 +1,89 @@
+package transformer
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// markdownEscaper escapes Discord markdown anywhere in a line and breaks up
+// mass mentions with a zero-width space
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+	`|`, `\|`,
+	`[`, `\[`,
+	`]`, `\]`,
+	"@everyone", "@\u200beveryone",
+	"@here", "@\u200bhere",
+)
+
+// urlPattern matches the bare links Discord turns into links by itself.
+// Escaping them would keep the backslashes in the link, so they are left as
+// they are.
+var urlPattern = regexp.MustCompile(`https?://[^\s<]+[^<.,:;"'\]\s]`)
+
+// escapeMarkdown makes s display literally in Discord: formatting characters
+// are escaped, as are headings, quotes and lists at the start of a line.
+// Links are left unescaped.
+func escapeMarkdown(s string) string {
+	var escaped strings.Builder
+	last := 0
+	for _, span := range urlPattern.FindAllStringIndex(s, -1) {
+		escaped.WriteString(markdownEscaper.Replace(s[last:span[0]]))
+		escaped.WriteString(s[span[0]:span[1]])
+		last = span[1]
+	}
+	escaped.WriteString(markdownEscaper.Replace(s[last:]))
+
+	lines := strings.Split(escaped.String(), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && strings.ContainsRune("#>-+", rune(trimmed[0])) {
+			lines[i] = line[:len(line)-len(trimmed)] + `\` + trimmed
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeLabels returns a copy of labels with escaped values, leaving the
+// values of the given keys, or glob patterns of them, as they are
+func escapeLabels(labels map[string]string, keep []string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	escaped := maps.Clone(labels)
+	for key, value := range escaped {
+		if matchAny(keep, key) < 0 {
+			escaped[key] = escapeMarkdown(value)
+		}
+	}
+	return escaped
+}
+
+// escapeAlert returns a copy of the alert with escaped labels and
+// annotations, except the annotations that are markdown on purpose
+func escapeAlert(alert grafana.Alert, markdownAnnotations []string) grafana.Alert {
+	alert.Labels = escapeLabels(alert.Labels, nil)
+	alert.Annotations = escapeLabels(alert.Annotations, markdownAnnotations)
+	return alert
+}
+
+// escapePayload returns a copy of the payload with escaped alerts, group and
+// common labels and common annotations
+func escapePayload(payload *grafana.WebhookPayload, markdownAnnotations []string) *grafana.WebhookPayload {
+	escaped := *payload
+	escaped.GroupLabels = escapeLabels(payload.GroupLabels, nil)
+	escaped.CommonLabels = escapeLabels(payload.CommonLabels, nil)
+	escaped.CommonAnnotations = escapeLabels(payload.CommonAnnotations, markdownAnnotations)
+	escaped.Alerts = make([]grafana.Alert, len(payload.Alerts))
+	for i, alert := range payload.Alerts {
+		escaped.Alerts[i] = escapeAlert(alert, markdownAnnotations)
+	}
+	return &escaped
+}
@@ -0,0 +1,104 @@
+package transformer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"**bold** and _italic_", `\*\*bold\*\* and \_italic\_`},
+		{"~~strike~~ ||spoiler||", `\~\~strike\~\~ \|\|spoiler\|\|`},
+		{"`code`", "\\`code\\`"},
+		{`C:\temp`, `C:\\temp`},
+		{"[link](https://example.com)", `\[link\](https://example.com)`},
+		{"# heading\n> quote\n - item\nnode-1 #2", "\\# heading\n\\> quote\n \\- item\nnode-1 #2"},
+		{"ping @everyone and @here", "ping @\u200beveryone and @\u200bhere"},
+		{"Runbook: https://wiki.example.com/disk_full.", `Runbook: https://wiki.example.com/disk_full.`},
+		{"*see* http://a.example.com/x_y?q=*, then _b_", `\*see\* http://a.example.com/x_y?q=*, then \_b\_`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeMarkdown(tt.in); got != tt.want {
+			t.Errorf("escapeMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGrafanaToDiscord_Escaping(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		ExternalURL: "https://grafana.example.com",
+		Alerts: []grafana.Alert{
+			{
+				Status: "firing",
+				Labels: map[string]string{"alertname": "Disk_Full", "namespace": "team*a"},
+				Annotations: map[string]string{
+					"summary":     "@everyone disk is *full*",
+					"description": "See the [runbook](https://wiki.example.com/disk_full)",
+				},
+			},
+		},
+	}
+
+	msg := GrafanaToDiscord(payload)[0]
+	field := msg.Embeds[0].Fields[0]
+
+	if field.Name != `Disk\_Full` {
+		t.Errorf("field name = %q, want the escaped alert name", field.Name)
+	}
+	for _, want := range []string{
+		"**Summary:** @\u200beveryone disk is \\*full\\*",
+		`**Description:** See the \[runbook\](https://wiki.example.com/disk_full)`,
+		`**Namespace:** team\*a`,
+		// Links are built from the original labels
+		"matcher=alertname%3DDisk_Full",
+	} {
+		if !strings.Contains(field.Value, want) {
+			t.Errorf("field value = %q, want it to contain %q", field.Value, want)
+		}
+	}
+
+	msg = GrafanaToDiscord(payload, WithMarkdownAnnotations("description"))[0]
+	if want := "See the [runbook](https://wiki.example.com/disk_full)"; !strings.Contains(msg.Embeds[0].Fields[0].Value, want) {
+		t.Errorf("field value = %q, want the markdown annotation unescaped", msg.Embeds[0].Fields[0].Value)
+	}
+
+	payload.CommonAnnotations = map[string]string{"summary": "*all* disks"}
+	summary := GrafanaToDiscord(payload, WithLayout(LayoutSummary))[0].Embeds[0]
+	if !strings.Contains(summary.Description, `\*all\* disks`) {
+		t.Errorf("summary = %q, want the escaped common summary", summary.Description)
+	}
+}
+
+func TestGrafanaToDiscord_AllowedMentions(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		Alerts: []grafana.Alert{{Status: "firing", Labels: map[string]string{"alertname": "HighCPU"}}},
+	}
+
+	for _, opts := range [][]Option{
+		nil,
+		{WithGrouping()},
+		{WithLayout(LayoutSummary)},
+	} {
+		msg := GrafanaToDiscord(payload, opts...)[0]
+		body, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), `"allowed_mentions":{"parse":[]}`) {
+			t.Errorf("message = %s, want allowed_mentions with an empty parse list", body)
+		}
+	}
+
+	msg := GrafanaToDiscord(payload, WithAllowedMentions("roles"))[0]
+	if got := msg.AllowedMentions.Parse; len(got) != 1 || got[0] != "roles" {
+		t.Errorf("allowed mentions = %v, want [roles]", got)
+	}
+}
@@ -0,0 +1,160 @@
+package transformer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// Color is an embed color. In JSON it is either a number or a "#rrggbb"
+// string.
+type Color int
+
+// UnmarshalJSON accepts both 14037554 and "#d63232"
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid color %s: want a number or \"#rrggbb\"", data)
+		}
+		*c = Color(n)
+		return nil
+	}
+
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 24)
+	if err != nil || !strings.HasPrefix(s, "#") || len(s) != 7 {
+		return fmt.Errorf("invalid color %q: want \"#rrggbb\"", s)
+	}
+	*c = Color(n)
+	return nil
+}
+
+// SeverityLevel describes how alerts of one severity level look
+type SeverityLevel struct {
+	// Name is the label value selecting the level, matched case-insensitively
+	Name  string `json:"name"`
+	Color Color  `json:"color"`
+	Emoji string `json:"emoji"`
+	Title string `json:"title"`
+	// ShowStatus shows the firing/resolved status. Levels without it, such as
+	// informational notifications, look the same whatever their status.
+	ShowStatus bool `json:"showStatus"`
+}
+
+// SeverityProfile maps the values of a severity label to the look of alerts
+type SeverityProfile struct {
+	// Label is the name of the label holding the severity
+	Label string `json:"label"`
+	// Levels are ordered from most to least severe
+	Levels []SeverityLevel `json:"levels"`
+	// Default names the level of alerts without a known severity. When empty,
+	// the least severe level is used.
+	Default string `json:"default"`
+	// Resolved is the look of resolved alerts of levels that show their
+	// status. Profiles loaded from a file keep the built-in look for what
+	// they leave out.
+	Resolved SeverityLevel `json:"resolved"`
+}
+
+// DefaultSeverityProfile returns the built-in profile for the severity label:
+// critical, warning (the default) and the informational info and
+// notification levels
+func DefaultSeverityProfile() *SeverityProfile {
+	return &SeverityProfile{
+		Label: "severity",
+		Levels: []SeverityLevel{
+			{Name: "critical", Color: colorFiring, Emoji: "🔥", Title: "Critical Alert Firing", ShowStatus: true},
+			{Name: "warning", Color: colorWarning, Emoji: "⚠️", Title: "Warning Alert Firing", ShowStatus: true},
+			{Name: "info", Color: colorNotification, Emoji: "ℹ️", Title: "Notification"},
+			{Name: "notification", Color: colorNotification, Emoji: "ℹ️", Title: "Notification"},
+		},
+		Default:  "warning",
+		Resolved: SeverityLevel{Color: colorResolved, Emoji: "✅", Title: "Alert Resolved"},
+	}
+}
+
+// LoadSeverityProfile reads a severity profile from a JSON file
+func LoadSeverityProfile(path string) (*SeverityProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := SeverityProfile{Resolved: DefaultSeverityProfile().Resolved}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("invalid severity profile %s: %w", path, err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid severity profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// Validate checks that the profile names a label, has uniquely named levels
+// and that its default level exists
+func (p *SeverityProfile) Validate() error {
+	if p.Label == "" {
+		return errors.New("label is required")
+	}
+	if len(p.Levels) == 0 {
+		return errors.New("at least one level is required")
+	}
+
+	seen := make(map[string]bool, len(p.Levels))
+	for i, level := range p.Levels {
+		name := strings.ToLower(level.Name)
+		if name == "" {
+			return fmt.Errorf("level %d has no name", i)
+		}
+		if seen[name] {
+			return fmt.Errorf("level %q is defined twice", level.Name)
+		}
+		seen[name] = true
+	}
+	if p.Default != "" && !seen[strings.ToLower(p.Default)] {
+		return fmt.Errorf("default level %q is not defined", p.Default)
+	}
+	return nil
+}
+
+// rank returns the position of the alert's level, 0 being the most severe
+func (p *SeverityProfile) rank(alert grafana.Alert) int {
+	if i := p.find(alert.Labels[p.Label]); i >= 0 {
+		return i
+	}
+	if i := p.find(p.Default); i >= 0 {
+		return i
+	}
+	return len(p.Levels) - 1
+}
+
+func (p *SeverityProfile) find(name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, level := range p.Levels {
+		if strings.EqualFold(level.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// style returns the look of the alert: its level, or the resolved look when
+// a level that shows its status has resolved
+func (p *SeverityProfile) style(alert grafana.Alert) SeverityLevel {
+	level := p.Levels[p.rank(alert)]
+	if level.ShowStatus && alert.Status != "firing" {
+		resolved := p.Resolved
+		resolved.Name = level.Name
+		resolved.ShowStatus = true
+		return resolved
+	}
+	return level
+}
@@ -0,0 +1,190 @@
+package transformer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+const priorityProfile = `{
+	"label": "priority",
+	"default": "P3",
+	"levels": [
+		{"name": "P1", "color": "#ff0000", "emoji": "🚨", "title": "P1 Incident", "showStatus": true},
+		{"name": "P2", "color": 16744448, "emoji": "🔥", "title": "P2 Alert", "showStatus": true},
+		{"name": "P3", "color": "#ffff00", "emoji": "⚠️", "title": "P3 Alert", "showStatus": true},
+		{"name": "P5", "color": "#888888", "emoji": "📝", "title": "FYI"}
+	],
+	"resolved": {"color": "#00ff00", "emoji": "🟢", "title": "Recovered"}
+}`
+
+func loadPriorityProfile(t *testing.T) *SeverityProfile {
+	t.Helper()
+	return loadProfile(t, priorityProfile)
+}
+
+func loadProfile(t *testing.T, body string) *SeverityProfile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "severity.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := LoadSeverityProfile(path)
+	if err != nil {
+		t.Fatalf("LoadSeverityProfile() error = %v", err)
+	}
+	return profile
+}
+
+func TestSeverityProfile(t *testing.T) {
+	profile := loadPriorityProfile(t)
+
+	tests := []struct {
+		name       string
+		alert      grafana.Alert
+		wantTitle  string
+		wantColor  int
+		wantStatus bool
+	}{
+		{
+			name:       "most severe level",
+			alert:      grafana.Alert{Status: "firing", Labels: map[string]string{"alertname": "Down", "priority": "P1"}},
+			wantTitle:  "🚨 P1 Incident",
+			wantColor:  0xff0000,
+			wantStatus: true,
+		},
+		{
+			name:       "numeric color and case-insensitive match",
+			alert:      grafana.Alert{Status: "firing", Labels: map[string]string{"alertname": "Down", "priority": "p2"}},
+			wantTitle:  "🔥 P2 Alert",
+			wantColor:  16744448,
+			wantStatus: true,
+		},
+		{
+			name:       "unknown level uses the default",
+			alert:      grafana.Alert{Status: "firing", Labels: map[string]string{"alertname": "Down", "priority": "P4"}},
+			wantTitle:  "⚠️ P3 Alert",
+			wantColor:  0xffff00,
+			wantStatus: true,
+		},
+		{
+			name:       "resolved",
+			alert:      grafana.Alert{Status: "resolved", Labels: map[string]string{"alertname": "Down", "priority": "P1"}},
+			wantTitle:  "🟢 Recovered",
+			wantColor:  0x00ff00,
+			wantStatus: true,
+		},
+		{
+			name:       "level without status ignores resolution",
+			alert:      grafana.Alert{Status: "resolved", Labels: map[string]string{"alertname": "Deploy", "priority": "P5"}},
+			wantTitle:  "📝 FYI",
+			wantColor:  0x888888,
+			wantStatus: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := &grafana.WebhookPayload{Alerts: []grafana.Alert{tt.alert}}
+			embed := GrafanaToDiscord(payload, WithSeverityProfile(profile))[0].Embeds[0]
+
+			if embed.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", embed.Title, tt.wantTitle)
+			}
+			if embed.Color != tt.wantColor {
+				t.Errorf("color = %d, want %d", embed.Color, tt.wantColor)
+			}
+			if got := contains(embed.Fields[0].Value, "**Status:**"); got != tt.wantStatus {
+				t.Errorf("status shown = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSeverityProfile_Summary(t *testing.T) {
+	profile := loadPriorityProfile(t)
+	payload := &grafana.WebhookPayload{
+		GroupLabels: map[string]string{"alertname": "Down"},
+		Alerts: []grafana.Alert{
+			{Status: "firing", Labels: map[string]string{"priority": "P3", "instance": "a"}},
+			{Status: "firing", Labels: map[string]string{"priority": "P2", "instance": "b"}},
+			{Status: "resolved", Labels: map[string]string{"priority": "P1", "instance": "c"}},
+		},
+	}
+
+	embed := GrafanaToDiscord(payload, WithSeverityProfile(profile), WithLayout(LayoutSummary))[0].Embeds[0]
+	if embed.Title != "🔥 Down" {
+		t.Errorf("title = %q, want the most severe firing level %q", embed.Title, "🔥 Down")
+	}
+	if embed.Color != 16744448 {
+		t.Errorf("color = %d, want %d", embed.Color, 16744448)
+	}
+}
+
+func TestLoadSeverityProfile_DefaultResolved(t *testing.T) {
+	profile := loadProfile(t, `{
+		"label": "priority",
+		"levels": [{"name": "P1", "color": "#ff0000", "emoji": "🚨", "title": "P1 Incident", "showStatus": true}]
+	}`)
+
+	alert := grafana.Alert{Status: "resolved", Labels: map[string]string{"alertname": "HighCPU", "priority": "P1"}}
+	embed := GrafanaToDiscord(&grafana.WebhookPayload{Alerts: []grafana.Alert{alert}}, WithSeverityProfile(profile))[0].Embeds[0]
+	if embed.Title != "✅ Alert Resolved" || embed.Color != colorResolved {
+		t.Errorf("resolved embed = %q/%d, want the built-in resolved look", embed.Title, embed.Color)
+	}
+}
+
+func TestSeverityProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile SeverityProfile
+	}{
+		{name: "no label", profile: SeverityProfile{Levels: []SeverityLevel{{Name: "high"}}}},
+		{name: "no levels", profile: SeverityProfile{Label: "level"}},
+		{name: "unnamed level", profile: SeverityProfile{Label: "level", Levels: []SeverityLevel{{Title: "High"}}}},
+		{name: "duplicate level", profile: SeverityProfile{Label: "level", Levels: []SeverityLevel{{Name: "page"}, {Name: "Page"}}}},
+		{name: "unknown default", profile: SeverityProfile{Label: "level", Levels: []SeverityLevel{{Name: "page"}}, Default: "ticket"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.profile.Validate(); err == nil {
+				t.Error("Validate() succeeded, want error")
+			}
+		})
+	}
+
+	if err := DefaultSeverityProfile().Validate(); err != nil {
+		t.Errorf("DefaultSeverityProfile().Validate() error = %v", err)
+	}
+}
+
+func TestColor_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    Color
+		wantErr bool
+	}{
+		{json: `14037554`, want: 14037554},
+		{json: `"#d63232"`, want: 0xd63232},
+		{json: `"d63232"`, wantErr: true},
+		{json: `"#fff"`, wantErr: true},
+		{json: `"#gggggg"`, wantErr: true},
+		{json: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var c Color
+		err := json.Unmarshal([]byte(tt.json), &c)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && c != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.json, c, tt.want)
+		}
+	}
+}
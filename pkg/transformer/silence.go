@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// SilenceOptions controls the silence links of alerts
+type SilenceOptions struct {
+	// ExcludeLabels lists glob patterns of volatile labels, such as values
+	// that change between evaluations, left out of the silence matchers
+	ExcludeLabels []string
+	// Duration prefills the duration of the silence; zero keeps Grafana's
+	// default
+	Duration time.Duration
+	// Comment prefills the comment of the silence
+	Comment string
+}
+
+// buildSilenceURL links to a new Grafana silence matching the alert's labels,
+// in the organization the alert belongs to. It returns an empty string if
+// the external URL is invalid.
+func buildSilenceURL(externalURL string, alert grafana.Alert, opts SilenceOptions) string {
+	base, err := url.Parse(externalURL)
+	if err != nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(alert.Labels))
+	for key := range alert.Labels {
+		if matchAny(opts.ExcludeLabels, key) < 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	query := url.Values{"alertmanager": {"grafana"}}
+	for _, key := range keys {
+		query.Add("matcher", key+"="+alert.Labels[key])
+	}
+	if opts.Duration > 0 {
+		query.Set("duration", formatSilenceDuration(opts.Duration))
+	}
+	if opts.Comment != "" {
+		query.Set("comment", opts.Comment)
+	}
+	if orgID := getOrgID(alert.GeneratorURL, externalURL); orgID != "" {
+		query.Set("orgId", orgID)
+	}
+
+	silenceURL := url.URL{
+		Scheme:   base.Scheme,
+		User:     base.User,
+		Host:     base.Host,
+		Path:     strings.TrimSuffix(base.Path, "/") + "/alerting/silence/new",
+		RawQuery: query.Encode(),
+	}
+	return silenceURL.String()
+}
+
+// getOrgID returns the orgId query parameter of the first URL that has one
+func getOrgID(urls ...string) string {
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if orgID := u.Query().Get("orgId"); orgID != "" {
+			return orgID
+		}
+	}
+	return ""
+}
+
+// formatSilenceDuration formats d the way Grafana's silence editor accepts
+// it, e.g. "1d2h30m"
+func formatSilenceDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "1m"
+	}
+
+	var b strings.Builder
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dd", days)
+		d -= days * 24 * time.Hour
+	}
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dh", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dm", minutes)
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func TestBuildSilenceURL(t *testing.T) {
+	labels := map[string]string{
+		"alertname":          "High CPU",
+		"namespace":          "prod&test",
+		"pod":                "api=0",
+		"__alert_rule_uid__": "abc123",
+		"value":              "97.5",
+	}
+
+	tests := []struct {
+		name        string
+		externalURL string
+		alert       grafana.Alert
+		opts        SilenceOptions
+		want        string
+	}{
+		{
+			name:        "encoded and ordered matchers",
+			externalURL: "https://grafana.example.com/",
+			alert:       grafana.Alert{Labels: map[string]string{"pod": "api=0", "alertname": "High CPU", "namespace": "prod&test"}},
+			want:        "https://grafana.example.com/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHigh+CPU&matcher=namespace%3Dprod%26test&matcher=pod%3Dapi%3D0",
+		},
+		{
+			name:        "org ID from generator URL",
+			externalURL: "https://grafana.example.com/grafana?orgId=1",
+			alert: grafana.Alert{
+				Labels:       map[string]string{"alertname": "HighCPU"},
+				GeneratorURL: "https://grafana.example.com/grafana/alerting/grafana/abc/view?orgId=7",
+			},
+			want: "https://grafana.example.com/grafana/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHighCPU&orgId=7",
+		},
+		{
+			name:        "org ID from external URL",
+			externalURL: "https://grafana.example.com?orgId=3",
+			alert:       grafana.Alert{Labels: map[string]string{"alertname": "HighCPU"}},
+			want:        "https://grafana.example.com/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHighCPU&orgId=3",
+		},
+		{
+			name:        "no org ID",
+			externalURL: "https://grafana.example.com",
+			alert:       grafana.Alert{Labels: map[string]string{"alertname": "HighCPU"}, GeneratorURL: "https://grafana.example.com/alerting/grafana/abc/view"},
+			want:        "https://grafana.example.com/alerting/silence/new?alertmanager=grafana&matcher=alertname%3DHighCPU",
+		},
+		{
+			name:        "volatile labels, duration and comment",
+			externalURL: "https://grafana.example.com",
+			alert:       grafana.Alert{Labels: labels},
+			opts: SilenceOptions{
+				ExcludeLabels: []string{"__*", "value", "namespace", "pod"},
+				Duration:      26*time.Hour + 30*time.Minute,
+				Comment:       "Silenced from Discord",
+			},
+			want: "https://grafana.example.com/alerting/silence/new?alertmanager=grafana&comment=Silenced+from+Discord&duration=1d2h30m&matcher=alertname%3DHigh+CPU",
+		},
+		{
+			name:        "invalid external URL",
+			externalURL: "://grafana",
+			alert:       grafana.Alert{Labels: map[string]string{"alertname": "HighCPU"}},
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch map iteration order leaking into the link
+			for i := 0; i < 10; i++ {
+				if got := buildSilenceURL(tt.externalURL, tt.alert, tt.opts); got != tt.want {
+					t.Fatalf("buildSilenceURL() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatSilenceDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{10 * time.Second, "1m"},
+		{30 * time.Minute, "30m"},
+		{2 * time.Hour, "2h"},
+		{90 * time.Minute, "1h30m"},
+		{7 * 24 * time.Hour, "7d"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSilenceDuration(tt.d); got != tt.want {
+			t.Errorf("formatSilenceDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package transformer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// summaryOverflowLength is the room kept in the summary description for the
+// "…and N more" line
+const summaryOverflowLength = 32
+
+// summaryMessages renders all alerts of a notification as a single summary
+// embed: a header from the group labels, the common summary, the number of
+// firing and resolved alerts and one line per alert
+func summaryMessages(payload *grafana.WebhookPayload, o options) []discord.Message {
+	if len(payload.Alerts) == 0 {
+		return nil
+	}
+
+	firing := 0
+	for _, alert := range payload.Alerts {
+		if alert.Status == "firing" {
+			firing++
+		}
+	}
+	resolved := len(payload.Alerts) - firing
+
+	// The most severe alert sets the look of the summary, firing alerts first
+	lead := -1
+	for i, alert := range payload.Alerts {
+		if firing > 0 && alert.Status != "firing" {
+			continue
+		}
+		if lead < 0 || o.severity.rank(alert) < o.severity.rank(payload.Alerts[lead]) {
+			lead = i
+		}
+	}
+	style := o.severity.style(payload.Alerts[lead])
+
+	escaped := escapePayload(payload, o.markdownAnnotations)
+
+	var header strings.Builder
+	if summary := escaped.CommonAnnotations["summary"]; summary != "" {
+		header.WriteString(summary + "\n")
+	}
+	fmt.Fprintf(&header, "**Firing:** %d • **Resolved:** %d\n", firing, resolved)
+
+	description := header.String() + "\n"
+	for i, alert := range payload.Alerts {
+		line := getSummaryLine(alert, escaped.Alerts[i], payload.CommonLabels, o) + "\n"
+		if utf8.RuneCountInString(description+line) > discord.MaxEmbedDescriptionLength-summaryOverflowLength {
+			description += fmt.Sprintf("…and %d more", len(payload.Alerts)-i)
+			break
+		}
+		description += line
+	}
+
+	// Username, content and footer come from the templates, rendered for the
+	// first alert
+	first := newTemplateData(escaped, payload.Alerts[0], o)
+	embed := discord.Embed{
+		Title:       strings.TrimSpace(style.Emoji + " " + getSummaryTitle(escaped)),
+		Description: strings.TrimSuffix(description, "\n"),
+		Type:        "rich",
+		URL:         first.AlertingURL,
+		Color:       int(style.Color),
+	}
+	if footer := o.templates.execute(templateFooter, first); footer != "" {
+		embed.Footer = &discord.EmbedFooter{Text: footer, IconURL: grafanaIconURL}
+	}
+
+	msg := newMessage(first, o)
+	msg.Embeds = []discord.Embed{embed}
+	if o.threadNames {
+		msg.ThreadName = getThreadName(grafana.Alert{Labels: payload.CommonLabels})
+	}
+
+	return []discord.Message{msg.Truncate()}
+}
+
+// getSummaryTitle builds the summary header from the group label values,
+// ordered by label name
+func getSummaryTitle(payload *grafana.WebhookPayload) string {
+	keys := make([]string, 0, len(payload.GroupLabels))
+	for key := range payload.GroupLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var values []string
+	for _, key := range keys {
+		if value := payload.GroupLabels[key]; value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		if alertname := payload.CommonLabels["alertname"]; alertname != "" {
+			return alertname
+		}
+		return "Grafana Alerts"
+	}
+	return strings.Join(values, " - ")
+}
+
+// getSummaryLine renders a single alert of the summary as
+// "🔥 `instance` • value • Firing", in the look of the alert's severity. The
+// status is left out for levels that don't show it. The instance is shown as
+// code, so it is taken from the original alert; the rest from the escaped one.
+func getSummaryLine(alert, escaped grafana.Alert, commonLabels map[string]string, o options) string {
+	style := o.severity.style(alert)
+	status := ""
+	if style.ShowStatus {
+		if alert.Status == "resolved" {
+			status = "Resolved"
+			if d := getDuration(alert); d > 0 {
+				status += " after " + humanizeDuration(d)
+			}
+		} else {
+			status = "Firing"
+			if since := relativeTime(alert.StartsAt); since != "" {
+				status += " since " + since
+			}
+		}
+	}
+
+	instance := strings.ReplaceAll(getInstance(alert, commonLabels), "`", "'")
+	parts := []string{strings.TrimSpace(fmt.Sprintf("%s `%s`", style.Emoji, instance))}
+	if values := getValues(alert, o.hiddenRefs); len(values) > 0 {
+		pairs := make([]string, 0, len(values))
+		for _, value := range values {
+			pairs = append(pairs, value.RefID+"="+value.Value)
+		}
+		parts = append(parts, strings.Join(pairs, ", "))
+	} else if values := escaped.Annotations["values"]; values != "" && len(alert.Values) == 0 {
+		parts = append(parts, values)
+	}
+	if status != "" {
+		parts = append(parts, status)
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// getInstance names the alert within its group: its instance label, else the
+// labels that set it apart from the rest of the group
+func getInstance(alert grafana.Alert, commonLabels map[string]string) string {
+	if instance := alert.Labels["instance"]; instance != "" {
+		return instance
+	}
+
+	var distinct []string
+	for key, value := range alert.Labels {
+		if _, ok := commonLabels[key]; !ok {
+			distinct = append(distinct, key+"="+value)
+		}
+	}
+	if len(distinct) > 0 {
+		sort.Strings(distinct)
+		return strings.Join(distinct, ", ")
+	}
+
+	if alertname := alert.Labels["alertname"]; alertname != "" {
+		return alertname
+	}
+	return alert.Fingerprint
+}
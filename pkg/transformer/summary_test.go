@@ -0,0 +1,151 @@
+package transformer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func TestGrafanaToDiscord_Summary(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		Status:            "firing",
+		ExternalURL:       "https://monitoring.example.com",
+		GroupLabels:       map[string]string{"alertname": "HighCPU", "namespace": "production"},
+		CommonLabels:      map[string]string{"alertname": "HighCPU", "namespace": "production"},
+		CommonAnnotations: map[string]string{"summary": "CPU usage is high"},
+		Alerts: []grafana.Alert{
+			{
+				Status:      "firing",
+				Labels:      map[string]string{"alertname": "HighCPU", "namespace": "production", "instance": "node-1", "severity": "critical"},
+				Annotations: map[string]string{"values": "A=95"},
+			},
+			{
+				Status: "firing",
+				Labels: map[string]string{"alertname": "HighCPU", "namespace": "production", "instance": "node-2"},
+			},
+			{
+				Status:   "resolved",
+				Labels:   map[string]string{"alertname": "HighCPU", "namespace": "production", "pod": "api-0"},
+				StartsAt: time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC),
+				EndsAt:   time.Date(2026, 2, 2, 10, 45, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	msgs := GrafanaToDiscord(payload, WithLayout(LayoutSummary))
+	if len(msgs) != 1 {
+		t.Fatalf("GrafanaToDiscord() returned %d messages, want 1", len(msgs))
+	}
+	if len(msgs[0].Embeds) != 1 {
+		t.Fatalf("summary has %d embeds, want 1", len(msgs[0].Embeds))
+	}
+
+	embed := msgs[0].Embeds[0]
+	if embed.Title != "🔥 HighCPU - production" {
+		t.Errorf("title = %q, want %q", embed.Title, "🔥 HighCPU - production")
+	}
+	if embed.Color != colorFiring {
+		t.Errorf("color = %d, want %d", embed.Color, colorFiring)
+	}
+	if embed.URL != "https://monitoring.example.com/alerting/list" {
+		t.Errorf("URL = %q, want the alert list", embed.URL)
+	}
+
+	for _, want := range []string{
+		"CPU usage is high",
+		"**Firing:** 2 • **Resolved:** 1",
+		"🔥 `node-1` • A=95 • Firing",
+		"⚠️ `node-2` • Firing",
+		"✅ `pod=api-0` • Resolved after 45m",
+	} {
+		if !strings.Contains(embed.Description, want) {
+			t.Errorf("description = %q, want it to contain %q", embed.Description, want)
+		}
+	}
+}
+
+func TestGrafanaToDiscord_SummaryInfo(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		Status:      "resolved",
+		GroupLabels: map[string]string{"alertname": "Deployment"},
+		Alerts: []grafana.Alert{
+			{Status: "resolved", Labels: map[string]string{"alertname": "Deployment", "instance": "api", "severity": "info"}},
+			{Status: "resolved", Labels: map[string]string{"alertname": "Deployment", "instance": "web", "severity": "info"}},
+		},
+	}
+
+	embed := GrafanaToDiscord(payload, WithLayout(LayoutSummary))[0].Embeds[0]
+	if embed.Title != "ℹ️ Deployment" || embed.Color != colorNotification {
+		t.Errorf("summary = %q/%d, want the informational look", embed.Title, embed.Color)
+	}
+	if !strings.Contains(embed.Description, "\nℹ️ `api`\nℹ️ `web`") {
+		t.Errorf("description = %q, want info lines without a status", embed.Description)
+	}
+}
+
+func TestGrafanaToDiscord_SummaryOverflow(t *testing.T) {
+	payload := &grafana.WebhookPayload{Status: "firing"}
+	for i := 0; i < 500; i++ {
+		payload.Alerts = append(payload.Alerts, grafana.Alert{
+			Status: "firing",
+			Labels: map[string]string{"alertname": "HighCPU", "instance": fmt.Sprintf("node-%03d.example.com:9100", i)},
+		})
+	}
+
+	msgs := GrafanaToDiscord(payload, WithLayout(LayoutSummary))
+	if len(msgs) != 1 {
+		t.Fatalf("GrafanaToDiscord() returned %d messages, want 1", len(msgs))
+	}
+	if err := msgs[0].Validate(); err != nil {
+		t.Errorf("summary is invalid: %v", err)
+	}
+	if description := msgs[0].Embeds[0].Description; !strings.Contains(description, "more") {
+		t.Errorf("description does not mention the omitted alerts: %q", description[len(description)-64:])
+	}
+}
+
+func TestGetSummaryTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload *grafana.WebhookPayload
+		want    string
+	}{
+		{
+			name:    "group labels ordered by name",
+			payload: &grafana.WebhookPayload{GroupLabels: map[string]string{"namespace": "production", "alertname": "HighCPU"}},
+			want:    "HighCPU - production",
+		},
+		{
+			name:    "falls back to common alert name",
+			payload: &grafana.WebhookPayload{CommonLabels: map[string]string{"alertname": "HighCPU"}},
+			want:    "HighCPU",
+		},
+		{
+			name:    "no labels",
+			payload: &grafana.WebhookPayload{},
+			want:    "Grafana Alerts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSummaryTitle(tt.payload); got != tt.want {
+				t.Errorf("getSummaryTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	for _, name := range []string{"alert", "summary"} {
+		if layout, err := ParseLayout(name); err != nil || string(layout) != name {
+			t.Errorf("ParseLayout(%q) = %q, %v, want %q", name, layout, err, name)
+		}
+	}
+	if _, err := ParseLayout("compact"); err == nil {
+		t.Error("ParseLayout(\"compact\") succeeded, want error")
+	}
+}
@@ -0,0 +1,208 @@
+package transformer
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// Names of the templates used to render a message
+const (
+	templateUsername    = "username"
+	templateContent     = "content"
+	templateTitle       = "title"
+	templateDescription = "description"
+	templateFieldName   = "field_name"
+	templateFieldValue  = "field_value"
+	templateFooter      = "footer"
+)
+
+//go:embed default.tmpl
+var defaultTemplateText string
+
+// defaultTemplates holds the built-in rendering
+var defaultTemplates = &Templates{
+	tmpl: template.Must(newTemplate("default").Parse(defaultTemplateText)),
+}
+
+// TemplateData is the data message templates are executed against
+type TemplateData struct {
+	// Payload is the whole Grafana notification
+	Payload *grafana.WebhookPayload
+	// Alert is the alert being rendered
+	Alert grafana.Alert
+	// Severity is the look of the alert, taking its status into account
+	Severity SeverityLevel
+	// Values are the alert's formatted query values, without hidden refIDs
+	Values []QueryValue
+	// Duration is how long a resolved alert was firing, or zero
+	Duration time.Duration
+	// AlertingURL links to Grafana's alert list, if the external URL is known
+	AlertingURL string
+	// SilenceURL opens a new silence matching the alert, if the external URL
+	// is known
+	SilenceURL string
+	// Links are the alert's action links: View Source, Dashboard, Panel and
+	// Silence, as far as they are known
+	Links []Link
+}
+
+// Link is a named action link
+type Link struct {
+	Name string
+	URL  string
+}
+
+// Templates renders the parts of a Discord message with text/template
+type Templates struct {
+	tmpl *template.Template
+}
+
+// DefaultTemplates returns the built-in templates
+func DefaultTemplates() *Templates {
+	return defaultTemplates
+}
+
+// LoadTemplates parses the template files matching pattern on top of the
+// built-in templates. A file may define any of the named templates with
+// {{define "title"}}...{{end}}; a file without definitions replaces the
+// template named after it, e.g. title.tmpl replaces "title". Templates that
+// are not overridden keep their built-in rendering.
+func LoadTemplates(pattern string) (*Templates, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template pattern %q: %w", pattern, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no template files match %q", pattern)
+	}
+
+	tmpl, err := defaultTemplates.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		text, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, err := tmpl.New(name).Parse(string(text)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Templates{tmpl: tmpl}, nil
+}
+
+// execute renders the named template with surrounding whitespace trimmed. If
+// the template fails, the alert is rendered with the built-in template
+// instead so that it is never lost.
+func (t *Templates) execute(name string, data *TemplateData) string {
+	var out strings.Builder
+	err := t.tmpl.ExecuteTemplate(&out, name, data)
+	if err != nil && t != defaultTemplates {
+		slog.Warn("Falling back to the built-in template", "template", name, "error", err)
+		return defaultTemplates.execute(name, data)
+	}
+	if err != nil {
+		slog.Error("Built-in template failed", "template", name, "error", err)
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// newTemplate creates an empty template with the helper functions
+func newTemplate(name string) *template.Template {
+	return template.New(name).Option("missingkey=zero").Funcs(template.FuncMap{
+		"humanizeDuration": humanizeDuration,
+		"since":            time.Since,
+		"relativeTime":     relativeTime,
+		"truncate":         truncateText,
+		"joinLabels":       joinLabels,
+		"toUpper":          strings.ToUpper,
+		"default":          defaultValue,
+	})
+}
+
+// humanizeDuration formats d with its two largest units, e.g. "1h 12m"
+func humanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	for i, unit := range units {
+		if d < unit.size {
+			continue
+		}
+		parts := []string{fmt.Sprintf("%d%s", d/unit.size, unit.suffix)}
+		if i+1 < len(units) {
+			next := units[i+1]
+			if n := d % unit.size / next.size; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d%s", n, next.suffix))
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return "0s"
+}
+
+// relativeTime formats t with Discord's timestamp markup, shown to each
+// reader relative to now in their own timezone, e.g. "3 hours ago". It
+// returns an empty string for the zero time.
+func relativeTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("<t:%d:R>", t.Unix())
+}
+
+// truncateText shortens s to at most limit runes, ending it with "…" when
+// cut. The limit comes first so that it can be used in pipelines.
+func truncateText(limit int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	if limit <= 0 {
+		return ""
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// joinLabels formats labels as "key=value" pairs ordered by key
+func joinLabels(sep string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, sep)
+}
+
+// defaultValue returns value, or def if value is empty
+func defaultValue(def, value any) any {
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return def
+	}
+	return value
+}
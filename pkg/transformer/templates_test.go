@@ -0,0 +1,157 @@
+package transformer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "*.tmpl")
+}
+
+func TestLoadTemplates(t *testing.T) {
+	pattern := writeTemplates(t, map[string]string{
+		"title.tmpl": "🚨 {{.Alert.Labels.alertname | toUpper}}\n",
+		"custom.tmpl": `{{define "username"}}{{.Alert.Labels.team | default "Alerts"}}{{end}}
+{{define "field_value"}}{{joinLabels ", " .Alert.Labels | truncate 20}}{{end}}
+{{define "footer"}}{{.Payload.Receiver}}{{end}}
+{{define "content"}}{{if eq .Alert.Status "firing"}}@here{{end}}{{end}}`,
+	})
+
+	templates, err := LoadTemplates(pattern)
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	payload := &grafana.WebhookPayload{
+		Receiver: "discord",
+		Alerts: []grafana.Alert{
+			{
+				Status: "firing",
+				Labels: map[string]string{"alertname": "HighCPU", "namespace": "production", "severity": "critical"},
+			},
+		},
+	}
+	msgs := GrafanaToDiscord(payload, WithTemplates(templates))
+	if len(msgs) != 1 {
+		t.Fatalf("GrafanaToDiscord() returned %d messages, want 1", len(msgs))
+	}
+
+	msg := msgs[0]
+	embed := msg.Embeds[0]
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"title", embed.Title, "🚨 HIGHCPU"},
+		{"username", msg.Username, "Alerts"},
+		{"content", msg.Content, "@here"},
+		{"field name", embed.Fields[0].Name, "HighCPU"},
+		{"field value", embed.Fields[0].Value, "alertname=HighCPU, …"},
+		{"footer", embed.Footer.Text, "discord"},
+		{"description", embed.Description, ""},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadTemplates_Errors(t *testing.T) {
+	if _, err := LoadTemplates(filepath.Join(t.TempDir(), "*.tmpl")); err == nil {
+		t.Error("LoadTemplates() with no matching files succeeded, want error")
+	}
+
+	pattern := writeTemplates(t, map[string]string{"title.tmpl": "{{.Alert.Labels.alertname"})
+	if _, err := LoadTemplates(pattern); err == nil {
+		t.Error("LoadTemplates() with a syntax error succeeded, want error")
+	}
+}
+
+func TestTemplates_FallbackOnError(t *testing.T) {
+	pattern := writeTemplates(t, map[string]string{"title.tmpl": "{{humanizeDuration .Alert.Labels.alertname}}"})
+	templates, err := LoadTemplates(pattern)
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := newTemplateData(&grafana.WebhookPayload{}, grafana.Alert{Status: "resolved"}, defaultOptions())
+	if got := templates.execute(templateTitle, data); got != "✅ Alert Resolved" {
+		t.Errorf("title = %q, want the built-in title", got)
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{400 * time.Millisecond, "0s"},
+		{42 * time.Second, "42s"},
+		{5*time.Minute + 3*time.Second, "5m 3s"},
+		{time.Hour + 12*time.Minute + 30*time.Second, "1h 12m"},
+		{time.Hour + 30*time.Second, "1h"},
+		{50*time.Hour + 5*time.Minute, "2d 2h"},
+		{-90 * time.Second, "1m 30s"},
+	}
+
+	for _, tt := range tests {
+		if got := humanizeDuration(tt.d); got != tt.want {
+			t.Errorf("humanizeDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	if got := relativeTime(time.Unix(1770026400, 0)); got != "<t:1770026400:R>" {
+		t.Errorf("relativeTime() = %q, want %q", got, "<t:1770026400:R>")
+	}
+	if got := relativeTime(time.Time{}); got != "" {
+		t.Errorf("relativeTime(zero) = %q, want empty", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		limit int
+		s     string
+		want  string
+	}{
+		{10, "short", "short"},
+		{5, "exactly", "exac…"},
+		{3, "héllo", "hé…"},
+		{0, "anything", ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncateText(tt.limit, tt.s); got != tt.want {
+			t.Errorf("truncateText(%d, %q) = %q, want %q", tt.limit, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	if got := defaultValue("none", ""); got != "none" {
+		t.Errorf("defaultValue(\"none\", \"\") = %v, want none", got)
+	}
+	if got := defaultValue("none", nil); got != "none" {
+		t.Errorf("defaultValue(\"none\", nil) = %v, want none", got)
+	}
+	if got := defaultValue("none", "set"); got != "set" {
+		t.Errorf("defaultValue(\"none\", \"set\") = %v, want set", got)
+	}
+}
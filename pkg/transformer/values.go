@@ -0,0 +1,121 @@
+package transformer
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pretty-discord-alerts/pkg/discord"
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+// unitAnnotation selects how query values are formatted. It holds either a
+// unit for all values, e.g. "bytes", or per refID units, e.g.
+// "A=percent,B=bytes", optionally with a unit for the remaining refIDs.
+const unitAnnotation = "value_unit"
+
+// Units of query values; values without a unit are plain numbers
+const (
+	unitSI      = "si"
+	unitPercent = "percent"
+	unitBytes   = "bytes"
+)
+
+// QueryValue is a formatted query value of an alert
+type QueryValue struct {
+	// RefID is the query or expression the value comes from, e.g. "A"
+	RefID string
+	Value string
+}
+
+// getValues formats the alert's query values ordered by refID, leaving out
+// the hidden refIDs
+func getValues(alert grafana.Alert, hidden []string) []QueryValue {
+	units := parseUnits(alert.Annotations[unitAnnotation])
+
+	refIDs := make([]string, 0, len(alert.Values))
+	for refID := range alert.Values {
+		if matchAny(hidden, refID) < 0 {
+			refIDs = append(refIDs, refID)
+		}
+	}
+	sort.Strings(refIDs)
+
+	values := make([]QueryValue, 0, len(refIDs))
+	for _, refID := range refIDs {
+		unit, ok := units[refID]
+		if !ok {
+			unit = units[""]
+		}
+		values = append(values, QueryValue{RefID: refID, Value: formatValue(alert.Values[refID], unit)})
+	}
+	return values
+}
+
+// valueFields renders query values as at most limit inline embed fields
+func valueFields(values []QueryValue, limit int) []discord.EmbedField {
+	values = values[:min(len(values), max(limit, 0))]
+	fields := make([]discord.EmbedField, 0, len(values))
+	for _, value := range values {
+		fields = append(fields, discord.EmbedField{Name: value.RefID, Value: value.Value, Inline: true})
+	}
+	return fields
+}
+
+// parseUnits parses the unit annotation into units by refID, keyed "" for
+// the unit of the remaining refIDs
+func parseUnits(annotation string) map[string]string {
+	units := make(map[string]string)
+	for _, entry := range strings.Split(annotation, ",") {
+		refID, unit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			refID, unit = "", refID
+		}
+		if unit = strings.ToLower(strings.TrimSpace(unit)); unit != "" {
+			units[strings.TrimSpace(refID)] = unit
+		}
+	}
+	return units
+}
+
+// formatValue formats v in the given unit, e.g. "1.5k", "95.2%" or "3.4 GiB"
+func formatValue(v float64, unit string) string {
+	switch unit {
+	case unitSI:
+		return scale(v, 1000, []string{"", "k", "M", "G", "T", "P", "E"}, "")
+	case unitPercent:
+		return formatNumber(v) + "%"
+	case unitBytes:
+		return scale(v, 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}, " ")
+	default:
+		return formatNumber(v)
+	}
+}
+
+// scale divides v by base until it is below base and appends the prefix of
+// that step
+func scale(v, base float64, prefixes []string, sep string) string {
+	i := 0
+	for math.Abs(v) >= base && i < len(prefixes)-1 {
+		v /= base
+		i++
+	}
+	if prefixes[i] == "" {
+		return formatNumber(v)
+	}
+	return formatNumber(v) + sep + prefixes[i]
+}
+
+// formatNumber formats v with at most two decimals
+func formatNumber(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	v = math.Round(v*100) / 100
+	if v == 0 {
+		// Avoid "-0"
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
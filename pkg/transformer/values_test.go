@@ -0,0 +1,115 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/pretty-discord-alerts/pkg/grafana"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		v    float64
+		unit string
+		want string
+	}{
+		{22, "", "22"},
+		{95.23456, "", "95.23"},
+		{-0.001, "", "0"},
+		{1500, unitSI, "1.5k"},
+		{2_500_000, unitSI, "2.5M"},
+		{-1500, unitSI, "-1.5k"},
+		{999, unitSI, "999"},
+		{95.2, unitPercent, "95.2%"},
+		{512, unitBytes, "512 B"},
+		{1536, unitBytes, "1.5 KiB"},
+		{3.5 * 1024 * 1024 * 1024, unitBytes, "3.5 GiB"},
+		{42, "furlongs", "42"},
+		{math.NaN(), "", "NaN"},
+	}
+
+	for _, tt := range tests {
+		if got := formatValue(tt.v, tt.unit); got != tt.want {
+			t.Errorf("formatValue(%v, %q) = %q, want %q", tt.v, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		annotation string
+		want       map[string]string
+	}{
+		{"", map[string]string{}},
+		{"bytes", map[string]string{"": "bytes"}},
+		{"A=Percent, B=bytes", map[string]string{"A": "percent", "B": "bytes"}},
+		{"si,C=percent", map[string]string{"": "si", "C": "percent"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseUnits(tt.annotation); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("parseUnits(%q) = %v, want %v", tt.annotation, got, tt.want)
+		}
+	}
+}
+
+func TestGrafanaToDiscord_Values(t *testing.T) {
+	body := `{
+		"alerts": [{
+			"status": "firing",
+			"labels": {"alertname": "DiskFull"},
+			"annotations": {"values": "A=1.073741824e+09, B=93.5, C=1", "value_unit": "A=bytes,B=percent"},
+			"values": {"B": 93.5, "A": 1073741824, "C": 1}
+		}]
+	}`
+	var payload grafana.WebhookPayload
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	embed := GrafanaToDiscord(&payload, WithHiddenRefIDs("C"))[0].Embeds[0]
+
+	if strings.Contains(embed.Fields[0].Value, "Query Results") {
+		t.Errorf("field value = %q, want the raw values annotation replaced by fields", embed.Fields[0].Value)
+	}
+
+	var got []string
+	for _, field := range embed.Fields[1:] {
+		if !field.Inline {
+			t.Errorf("field %q is not inline", field.Name)
+		}
+		got = append(got, field.Name+"="+field.Value)
+	}
+	want := []string{"A=1 GiB", "B=93.5%"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("value fields = %v, want %v", got, want)
+	}
+
+	summary := GrafanaToDiscord(&payload, WithHiddenRefIDs("C"), WithLayout(LayoutSummary))[0].Embeds[0]
+	if !strings.Contains(summary.Description, "A=1 GiB, B=93.5%") {
+		t.Errorf("summary = %q, want it to contain the formatted values", summary.Description)
+	}
+}
+
+func TestGrafanaToDiscord_ValuesAnnotationFallback(t *testing.T) {
+	payload := &grafana.WebhookPayload{
+		Alerts: []grafana.Alert{
+			{
+				Status:      "firing",
+				Labels:      map[string]string{"alertname": "HighCPU"},
+				Annotations: map[string]string{"values": "B=22, C=1"},
+			},
+		},
+	}
+
+	embed := GrafanaToDiscord(payload)[0].Embeds[0]
+	if !strings.Contains(embed.Fields[0].Value, "**Query Results:** B=22, C=1") {
+		t.Errorf("field value = %q, want the values annotation without structured values", embed.Fields[0].Value)
+	}
+	if len(embed.Fields) != 1 {
+		t.Errorf("embed has %d fields, want 1", len(embed.Fields))
+	}
+}